@@ -0,0 +1,28 @@
+package collections
+
+type funcCodec[T any] struct {
+	marshalFn   MarshalFn[T]
+	unmarshalFn UnmarshalFn[T]
+}
+
+func createFuncCodec[T any](
+	marshalFn MarshalFn[T],
+	unmarshalFn UnmarshalFn[T],
+) Codec[T] {
+	out := funcCodec[T]{
+		marshalFn:   marshalFn,
+		unmarshalFn: unmarshalFn,
+	}
+
+	return &out
+}
+
+// Marshal encodes the value using the marshal func
+func (app *funcCodec[T]) Marshal(value T) ([]byte, error) {
+	return app.marshalFn(value)
+}
+
+// Unmarshal decodes the data using the unmarshal func
+func (app *funcCodec[T]) Unmarshal(data []byte) (T, error) {
+	return app.unmarshalFn(data)
+}
@@ -0,0 +1,32 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+type gobCodec[T any] struct {
+}
+
+func createGobCodec[T any]() Codec[T] {
+	out := gobCodec[T]{}
+	return &out
+}
+
+// Marshal encodes the value using gob
+func (app *gobCodec[T]) Marshal(value T) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	err := gob.NewEncoder(&buffer).Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Unmarshal decodes the gob data
+func (app *gobCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
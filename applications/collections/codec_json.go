@@ -0,0 +1,23 @@
+package collections
+
+import "encoding/json"
+
+type jsonCodec[T any] struct {
+}
+
+func createJSONCodec[T any]() Codec[T] {
+	out := jsonCodec[T]{}
+	return &out
+}
+
+// Marshal encodes the value using JSON
+func (app *jsonCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes the JSON data
+func (app *jsonCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
@@ -0,0 +1,111 @@
+package collections
+
+import (
+	"iter"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+type collection[T any] struct {
+	hashAdapter    hash.Adapter
+	contentBuilder contents.ContentBuilder
+	application    databases.Application
+	context        uint
+	kind           uint
+	codec          Codec[T]
+}
+
+func createCollection[T any](
+	hashAdapter hash.Adapter,
+	contentBuilder contents.ContentBuilder,
+	application databases.Application,
+	context uint,
+	kind uint,
+	codec Codec[T],
+) Collection[T] {
+	out := collection[T]{
+		hashAdapter:    hashAdapter,
+		contentBuilder: contentBuilder,
+		application:    application,
+		context:        context,
+		kind:           kind,
+		codec:          codec,
+	}
+
+	return &out
+}
+
+// Put encodes the value and adds it to the pending inserts of the context, then returns its hash
+func (app *collection[T]) Put(value T) (hash.Hash, error) {
+	data, err := app.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	pHash, err := app.hashAdapter.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := app.contentBuilder.Create().
+		WithHash(*pHash).
+		WithKind(app.kind).
+		WithData(data).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = app.application.Insert(app.context, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return *pHash, nil
+}
+
+// Get retrieves and decodes a committed value by hash
+func (app *collection[T]) Get(hash hash.Hash) (T, error) {
+	content, err := app.application.Retrieve(app.context, app.kind, hash)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	return app.codec.Unmarshal(content.Data())
+}
+
+// All iterates over the committed values, by insertion commit
+func (app *collection[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		options := databases.ScanOptions{
+			Limit: scanPageSize,
+			Order: databases.OrderByCommit,
+		}
+
+		for {
+			page, err := app.application.Scan(app.context, app.kind, options)
+			if err != nil {
+				var empty T
+				yield(empty, err)
+				return
+			}
+
+			for _, oneContentKey := range page {
+				value, err := app.Get(oneContentKey.Hash())
+				if !yield(value, err) || err != nil {
+					return
+				}
+			}
+
+			if uint(len(page)) < options.Limit {
+				return
+			}
+
+			options.After = page[len(page)-1]
+		}
+	}
+}
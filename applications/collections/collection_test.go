@@ -0,0 +1,232 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/infrastructure/memory"
+)
+
+type userForTests struct {
+	Name string
+	Age  uint
+}
+
+func TestCollection_putThenGet_thenAll_Success(t *testing.T) {
+	codecs := map[string]Codec[userForTests]{
+		"json": NewJSONCodec[userForTests](),
+		"gob":  NewGobCodec[userForTests](),
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			app, pContext := openForTests(t)
+			if pContext == nil {
+				return
+			}
+
+			defer app.Close(*pContext)
+
+			collection := NewCollection(app, *pContext, 0, codec)
+			expected := []userForTests{}
+			for i := 0; i < scanPageSize*2+10; i++ {
+				user := userForTests{
+					Name: fmt.Sprintf("user %d", i),
+					Age:  uint(i),
+				}
+
+				_, err := collection.Put(user)
+				if err != nil {
+					t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+					return
+				}
+
+				expected = append(expected, user)
+			}
+
+			err := app.Commit(*pContext)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			last := userForTests{
+				Name: "last user",
+				Age:  99,
+			}
+
+			lastHash, err := collection.Put(last)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			// the value is not committed yet:
+			_, err = collection.Get(lastHash)
+			if err == nil {
+				t.Errorf("the error was expected to be valid, nil returned")
+				return
+			}
+
+			err = app.Commit(*pContext)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			retLast, err := collection.Get(lastHash)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			if retLast != last {
+				t.Errorf("the returned value is invalid")
+				return
+			}
+
+			// the values committed first are iterated first:
+			expected = append(expected, last)
+			found := map[userForTests]bool{}
+			amount := 0
+			for oneUser, err := range collection.All() {
+				if err != nil {
+					t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+					return
+				}
+
+				if amount == len(expected)-1 && oneUser != last {
+					t.Errorf("the last committed value was expected to be iterated last")
+					return
+				}
+
+				found[oneUser] = true
+				amount++
+			}
+
+			if amount != len(expected) {
+				t.Errorf("%d values were expected, %d returned", len(expected), amount)
+				return
+			}
+
+			for _, oneUser := range expected {
+				if _, ok := found[oneUser]; !ok {
+					t.Errorf("the value (name: %s) was expected to be iterated", oneUser.Name)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestCollection_withFuncCodec_Success(t *testing.T) {
+	app, pContext := openForTests(t)
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+
+	codec := NewCodec(
+		func(value int) ([]byte, error) {
+			return []byte(strconv.Itoa(value)), nil
+		},
+		func(data []byte) (int, error) {
+			return strconv.Atoi(string(data))
+		},
+	)
+
+	collection := NewCollection(app, *pContext, 3, codec)
+	hash, err := collection.Put(42)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	value, err := collection.Get(hash)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if value != 42 {
+		t.Errorf("the value was expected to be %d, %d returned", 42, value)
+		return
+	}
+
+	// the other kinds are not part of the collection:
+	other := NewCollection(app, *pContext, 4, codec)
+	_, err = other.Get(hash)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	for range other.All() {
+		t.Errorf("the collection was expected to be empty")
+		return
+	}
+}
+
+func TestCollection_withCodecError_returnsError(t *testing.T) {
+	app, pContext := openForTests(t)
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+
+	codec := NewCodec(
+		func(value int) ([]byte, error) {
+			return nil, errors.New("this is a marshal error")
+		},
+		func(data []byte) (int, error) {
+			return 0, errors.New("this is an unmarshal error")
+		},
+	)
+
+	collection := NewCollection(app, *pContext, 0, codec)
+	_, err := collection.Put(42)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func openForTests(t *testing.T) (databases.Application, *uint) {
+	app, err := memory.NewApplication(nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil, nil
+	}
+
+	name := "my_name"
+	err = app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil, nil
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil, nil
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil, nil
+	}
+
+	return app, pContext
+}
@@ -0,0 +1,74 @@
+package collections
+
+import (
+	"iter"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const scanPageSize = 100
+
+// NewCollection creates a new collection of the values of a kind, using the context of the application
+func NewCollection[T any](
+	application databases.Application,
+	context uint,
+	kind uint,
+	codec Codec[T],
+) Collection[T] {
+	hashAdapter := hash.NewAdapter()
+	contentBuilder := contents.NewContentBuilder()
+	return createCollection(
+		hashAdapter,
+		contentBuilder,
+		application,
+		context,
+		kind,
+		codec,
+	)
+}
+
+// NewJSONCodec creates a new codec that encodes the values using JSON
+func NewJSONCodec[T any]() Codec[T] {
+	return createJSONCodec[T]()
+}
+
+// NewGobCodec creates a new codec that encodes the values using gob
+func NewGobCodec[T any]() Codec[T] {
+	return createGobCodec[T]()
+}
+
+// NewCodec creates a new codec from marshal and unmarshal funcs, such as the ones of protobuf messages
+func NewCodec[T any](
+	marshalFn MarshalFn[T],
+	unmarshalFn UnmarshalFn[T],
+) Codec[T] {
+	return createFuncCodec(marshalFn, unmarshalFn)
+}
+
+// MarshalFn encodes a value to bytes
+type MarshalFn[T any] func(value T) ([]byte, error)
+
+// UnmarshalFn decodes bytes to a value
+type UnmarshalFn[T any] func(data []byte) (T, error)
+
+// Codec represents the encoding of the values of a collection
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// Collection represents the typed values of a kind
+type Collection[T any] interface {
+	// Put encodes the value and adds it to the pending inserts of the context, then returns its hash.
+	// The value is persisted by the next commit of the context
+	Put(value T) (hash.Hash, error)
+
+	// Get retrieves and decodes a committed value by hash
+	Get(hash hash.Hash) (T, error)
+
+	// All iterates over the committed values, by insertion commit, scanning them page by page.
+	// The iteration stops after the first error
+	All() iter.Seq2[T, error]
+}
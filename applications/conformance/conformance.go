@@ -0,0 +1,488 @@
+package conformance
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+// CreateApplicationFn creates an empty Application instance and its cleanup func.  It returns a nil Application once
+// it reported the error that prevented its creation
+type CreateApplicationFn func() (databases.Application, func())
+
+// Execute executes the tests every Application implementation must pass
+func Execute(t *testing.T, createFn CreateApplicationFn) {
+	tests := map[string]func(t *testing.T, app databases.Application){
+		"exists_thenCreate_thenDelete": executeExistsThenCreateThenDeleteForTests,
+		"open":                         executeOpenForTests,
+		"lock_thenUnlock":              executeLockThenUnlockForTests,
+		"write_thenRead":               executeWriteThenReadForTests,
+		"copy":                         executeCopyForTests,
+		"insert_thenCommit":            executeInsertThenCommitForTests,
+		"erase_thenCommit":             executeEraseThenCommitForTests,
+		"commit_isPersisted":           executeCommitIsPersistedForTests,
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			app, cleanupFn := createFn()
+			defer cleanupFn()
+			if app == nil {
+				return
+			}
+
+			testFn(t, app)
+		})
+	}
+}
+
+func executeExistsThenCreateThenDeleteForTests(t *testing.T, app databases.Application) {
+	name := "my_name"
+	exists, err := app.Exists(name)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	if exists {
+		t.Errorf("the database was expected to NOT exists")
+		return
+	}
+
+	if err := app.Delete(name); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.New(name); !isNilForTests(t, err) {
+		return
+	}
+
+	exists, err = app.Exists(name)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	if !exists {
+		t.Errorf("the database was expected to exists")
+		return
+	}
+
+	if err := app.New(name); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Delete(name); !isNilForTests(t, err) {
+		return
+	}
+
+	exists, err = app.Exists(name)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	if exists {
+		t.Errorf("the database was expected to NOT exists")
+		return
+	}
+}
+
+func executeOpenForTests(t *testing.T, app databases.Application) {
+	name := "my_name"
+	if _, err := app.Open(name); !isErrorForTests(t, err) {
+		return
+	}
+
+	pContext := openForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	if _, err := app.Open(name); !isErrorForTests(t, err) {
+		return
+	}
+
+	if _, err := app.Reference(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Close(*pContext); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Close(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	if _, err := app.Read(*pContext, 0, 1); !isErrorForTests(t, err) {
+		return
+	}
+
+	pSecondContext, err := app.Open(name)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	defer app.Close(*pSecondContext)
+	if *pSecondContext == *pContext {
+		t.Errorf("the context (%d) was expected to be different than the closed context (%d)", *pSecondContext, *pContext)
+		return
+	}
+}
+
+func executeLockThenUnlockForTests(t *testing.T, app databases.Application) {
+	pContext := openForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	if err := app.Lock(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Unlock(*pContext); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Unlock(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Insert(*pContext, contents.NewContentForTests(0, []byte("this is some data"))); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Commit(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Lock(*pContext); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Commit(*pContext); !isNilForTests(t, err) {
+		return
+	}
+}
+
+func executeWriteThenReadForTests(t *testing.T, app databases.Application) {
+	pContext := openForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	if err := app.Write(*pContext, 0, []byte("this is some data")); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Write(*pContext, 8, []byte("my")); !isNilForTests(t, err) {
+		return
+	}
+
+	data, err := app.Read(*pContext, 5, 9)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	expected := []byte("is myme d")
+	if !bytes.Equal(expected, data) {
+		t.Errorf("the data was expected to be %s, %s returned", expected, data)
+		return
+	}
+
+	if _, err := app.Read(*pContext, 10, 20); !isErrorForTests(t, err) {
+		return
+	}
+}
+
+func executeCopyForTests(t *testing.T, app databases.Application) {
+	destination := "my_destination"
+	pDestinationContext := openForTests(t, app, destination)
+	if pDestinationContext == nil {
+		return
+	}
+
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the destination data")),
+	}
+
+	if !insertThenCommitForTests(t, app, *pDestinationContext, list) {
+		return
+	}
+
+	if err := app.Close(*pDestinationContext); !isNilForTests(t, err) {
+		return
+	}
+
+	pContext := openForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	source := contents.NewContentForTests(0, []byte("this is the source data"))
+	if !insertThenCommitForTests(t, app, *pContext, []contents.Content{source}) {
+		return
+	}
+
+	if err := app.Copy(*pContext, destination); !isNilForTests(t, err) {
+		return
+	}
+
+	exists, err := app.Exists(destination)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	if exists {
+		t.Errorf("the destination database was expected to NOT exists after the Copy")
+		return
+	}
+
+	if _, err := app.Retrieve(*pContext, source.Kind(), source.Hash()); !isErrorForTests(t, err) {
+		return
+	}
+
+	if !retrieveForTests(t, app, *pContext, list) {
+		return
+	}
+}
+
+func executeInsertThenCommitForTests(t *testing.T, app databases.Application) {
+	pContext := openForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	if err := app.Commit(*pContext); !isErrorForTests(t, err) {
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(1, []byte("this is the second data"))
+	list := []contents.Content{first, second}
+	if !insertThenCommitForTests(t, app, *pContext, list) {
+		return
+	}
+
+	if err := app.Insert(*pContext, first); !isErrorForTests(t, err) {
+		return
+	}
+
+	sameHashOtherKind := contents.NewContentForTests(1, []byte("this is the first data"))
+	if !insertThenCommitForTests(t, app, *pContext, []contents.Content{sameHashOtherKind}) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	commits := reference.Commits().List()
+	if len(commits) != 2 {
+		t.Errorf("%d commits were expected, %d returned", 2, len(commits))
+		return
+	}
+
+	if commits[0].HasParent() {
+		t.Errorf("the first commit was expected to NOT contain a parent")
+		return
+	}
+
+	if !commits[1].HasParent() || !commits[1].Parent().Compare(commits[0].Hash()) {
+		t.Errorf("the second commit was expected to contain the first commit as parent")
+		return
+	}
+
+	if !retrieveForTests(t, app, *pContext, append(list, sameHashOtherKind)) {
+		return
+	}
+}
+
+func executeEraseThenCommitForTests(t *testing.T, app databases.Application) {
+	pContext := openForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	if err := app.Erase(*pContext, first.Kind(), first.Hash()); !isErrorForTests(t, err) {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, *pContext, []contents.Content{first, second}) {
+		return
+	}
+
+	if err := app.Erase(*pContext, first.Kind(), first.Hash()); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Erase(*pContext, first.Kind(), first.Hash()); !isErrorForTests(t, err) {
+		return
+	}
+
+	if err := app.Commit(*pContext); !isNilForTests(t, err) {
+		return
+	}
+
+	if _, err := app.Retrieve(*pContext, first.Kind(), first.Hash()); !isErrorForTests(t, err) {
+		return
+	}
+
+	if !retrieveForTests(t, app, *pContext, []contents.Content{second}) {
+		return
+	}
+
+	if !eraseThenCommitForTests(t, app, *pContext, []contents.Content{second}) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	if reference.HasContentKeys() {
+		t.Errorf("the reference was expected to NOT contain contentKeys")
+		return
+	}
+
+	third := contents.NewContentForTests(0, []byte("this is the third data"))
+	if !insertThenCommitForTests(t, app, *pContext, []contents.Content{third}) {
+		return
+	}
+
+	if !retrieveForTests(t, app, *pContext, []contents.Content{third}) {
+		return
+	}
+}
+
+func executeCommitIsPersistedForTests(t *testing.T, app databases.Application) {
+	name := "my_name"
+	pContext := openForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+		contents.NewContentForTests(1, []byte("this is the second data")),
+	}
+
+	if !insertThenCommitForTests(t, app, *pContext, list) {
+		return
+	}
+
+	pending := contents.NewContentForTests(0, []byte("this is some uncommitted data"))
+	if err := app.Insert(*pContext, pending); !isNilForTests(t, err) {
+		return
+	}
+
+	if err := app.Close(*pContext); !isNilForTests(t, err) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if !isNilForTests(t, err) {
+		return
+	}
+
+	defer app.Close(*pContext)
+	if !retrieveForTests(t, app, *pContext, list) {
+		return
+	}
+
+	if _, err := app.Retrieve(*pContext, pending.Kind(), pending.Hash()); !isErrorForTests(t, err) {
+		return
+	}
+}
+
+func openForTests(t *testing.T, app databases.Application, name string) *uint {
+	err := app.New(name)
+	if !isNilForTests(t, err) {
+		return nil
+	}
+
+	pContext, err := app.Open(name)
+	if !isNilForTests(t, err) {
+		return nil
+	}
+
+	err = app.Lock(*pContext)
+	if !isNilForTests(t, err) {
+		return nil
+	}
+
+	return pContext
+}
+
+func insertThenCommitForTests(t *testing.T, app databases.Application, context uint, list []contents.Content) bool {
+	for _, oneContent := range list {
+		err := app.Insert(context, oneContent)
+		if !isNilForTests(t, err) {
+			return false
+		}
+	}
+
+	err := app.Commit(context)
+	if !isNilForTests(t, err) {
+		return false
+	}
+
+	return true
+}
+
+func eraseThenCommitForTests(t *testing.T, app databases.Application, context uint, list []contents.Content) bool {
+	for _, oneContent := range list {
+		if err := app.Erase(context, oneContent.Kind(), oneContent.Hash()); !isNilForTests(t, err) {
+			return false
+		}
+	}
+
+	err := app.Commit(context)
+	return isNilForTests(t, err)
+}
+
+func retrieveForTests(t *testing.T, app databases.Application, context uint, list []contents.Content) bool {
+	for _, oneContent := range list {
+		retContent, err := app.Retrieve(context, oneContent.Kind(), oneContent.Hash())
+		if !isNilForTests(t, err) {
+			return false
+		}
+
+		if !reflect.DeepEqual(oneContent, retContent) {
+			t.Errorf("the returned content (kind: %d, hash: %s) is invalid", oneContent.Kind(), oneContent.Hash().String())
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNilForTests reports the error, then returns true when there is none
+func isNilForTests(t *testing.T, err error) bool {
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return true
+}
+
+// isErrorForTests returns true when there is an error, reports its absence otherwise
+func isErrorForTests(t *testing.T, err error) bool {
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return false
+	}
+
+	return true
+}
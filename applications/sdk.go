@@ -1,18 +1,159 @@
 package databases
 
+import (
+	"time"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const (
+	// OperationOpen represents the opening of a context
+	OperationOpen = "open"
+
+	// OperationRead represents a read on the database of a context
+	OperationRead = "read"
+
+	// OperationWrite represents a write on the database of a context
+	OperationWrite = "write"
+
+	// OperationCommit represents a commit
+	OperationCommit = "commit"
+
+	// OperationLock represents the locking of the database of a context
+	OperationLock = "lock"
+)
+
+const (
+	// OrderByHash orders the contents by hash
+	OrderByHash uint = iota
+
+	// OrderByCommit orders the contents by the commit that inserted them, then by hash
+	OrderByCommit
+)
+
+// ScanOptions represents the options of a scan
+type ScanOptions struct {
+	// After is the cursor of the scan: the last content key of the previous page, if any.  It remains valid across
+	// commits, even when its content gets deleted
+	After references.ContentKey
+
+	// Limit is the maximum amount of content keys to return, zero (0) meaning unlimited
+	Limit uint
+
+	// Order is the order of the content keys, OrderByHash or OrderByCommit
+	Order uint
+}
+
+// Stats represents the statistics of a database
+type Stats struct {
+	// FileSize is the size of the database file, in bytes
+	FileSize uint
+
+	// HeaderSize is the size of the header and the reference, in bytes
+	HeaderSize uint
+
+	// DataSize is the size of the data region, in bytes
+	DataSize uint
+
+	// LiveBytes is the amount of bytes of the data region used by the contents of the reference
+	LiveBytes uint
+
+	// DeadBytes is the amount of bytes of the data region no longer used, such as the ones of the deleted contents
+	DeadBytes uint
+
+	// Commits is the amount of commits
+	Commits uint
+
+	// ContentsPerKind is the amount of contents, by kind
+	ContentsPerKind map[uint]uint
+
+	// AverageContentSize is the average amount of bytes a content uses in the data region
+	AverageContentSize float64
+
+	// Fragmentation is the ratio of dead bytes in the data region, from zero (0) to one (1)
+	Fragmentation float64
+}
+
+// CommitEvent represents a commit delivered to the subscribers of a database
+type CommitEvent struct {
+	// Name is the name of the database
+	Name string
+
+	// Commit is the commit
+	Commit references.Commit
+
+	// Inserted contains the content keys inserted by the commit, including the ones deleted since
+	Inserted []references.ContentKey
+
+	// Deleted contains the content keys deleted by the commit
+	Deleted []references.ContentKey
+}
+
+// CancelFn cancels a subscription and closes its channel
+type CancelFn func()
+
+// Instrumentation records the measurements of the operations of an application, and traces them using spans.  Only
+// the operations invoked on the application are recorded, not the ones it performs internally
+type Instrumentation interface {
+	// StartSpan starts the span of an operation on the database of the given name, then returns it.  The span is ended
+	// once the operation returns, right after its measurements are recorded
+	StartSpan(operation string, name string) Span
+
+	// RecordOperation records the latency of an operation, the amount of bytes it read or wrote, and its error, if any
+	RecordOperation(operation string, duration time.Duration, amount uint, err error)
+
+	// RecordLockWait records the time spent attempting to acquire the lock of a database, and the error of the attempt
+	// when the lock could not be acquired
+	RecordLockWait(duration time.Duration, err error)
+
+	// RecordCommit records the amount of bytes a commit appended to the data region, and its amount of inserts and deletes
+	RecordCommit(size uint, inserts uint, deletes uint)
+}
+
+// Span represents the span of a traced operation
+type Span interface {
+	// End ends the span, with the error of its operation, if any
+	End(err error)
+}
+
 // OnOpenFn represents the onOpen func
 type OnOpenFn func(context uint) error
 
+// OnBeforeCommitFn represents the func executed before a commit with its pending inserts and deletes.  Returning
+// an error vetoes the commit, the pending inserts and deletes are then kept
+type OnBeforeCommitFn func(context uint, inserts []contents.Content, deletes []references.ContentKey) error
+
+// OnAfterCommitFn represents the func executed once a commit is written
+type OnAfterCommitFn func(context uint, commit references.Commit)
+
 // Application represents the database application
 type Application interface {
 	Exists(name string) (bool, error)
 	New(name string) error
 	Delete(name string) error
 	Open(name string) (*uint, error)
+	OpenTag(name string, tag string) (*uint, error)
 	Lock(context uint) error
 	Unlock(context uint) error
 	Read(context uint, offset uint, length uint) ([]byte, error)
 	Write(context uint, offset int64, data []byte) error
 	Copy(context uint, destination string) error
+	Reference(context uint) (references.Reference, error)
+	Retrieve(context uint, kind uint, hash hash.Hash) (contents.Content, error)
+	Has(context uint, kind uint, hash hash.Hash) (bool, error)
+	Scan(context uint, kind uint, options ScanOptions) ([]references.ContentKey, error)
+	Insert(context uint, content contents.Content) error
+	Erase(context uint, kind uint, hash hash.Hash) error
+	Commit(context uint) error
+	CommitWithMetadata(context uint, metadata references.Metadata) error
+	Tag(context uint, name string, commit hash.Hash) error
+	ResolveTag(context uint, name string) (*hash.Hash, error)
+	ListTags(context uint) ([]references.Tag, error)
+	RegisterKind(context uint, kind references.Kind) error
+	ListKinds(context uint) ([]references.Kind, error)
+	Subscribe(name string, from hash.Hash) (<-chan CommitEvent, CancelFn, error)
+	Stats(context uint) (*Stats, error)
 	Close(context uint) error
 }
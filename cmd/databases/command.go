@@ -0,0 +1,623 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/databases/infrastructure/files"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const watchInterval = time.Second
+
+var errUsage = errors.New("the command or its arguments are invalid")
+
+type command struct {
+	application    files.Application
+	contentBuilder contents.ContentBuilder
+	hashAdapter    hash.Adapter
+	name           string
+	pTag           *string
+	schema         string
+	metadata       references.Metadata
+	output         io.Writer
+}
+
+func createCommand(
+	application files.Application,
+	name string,
+	pTag *string,
+	schema string,
+	metadata references.Metadata,
+	output io.Writer,
+) *command {
+	out := command{
+		application:    application,
+		contentBuilder: contents.NewContentBuilder(),
+		hashAdapter:    hash.NewAdapter(),
+		name:           name,
+		pTag:           pTag,
+		schema:         schema,
+		metadata:       metadata,
+		output:         output,
+	}
+
+	return &out
+}
+
+func (app *command) execute(name string, args []string) error {
+	switch name {
+	case "new":
+		return app.application.New(app.name)
+	case "delete":
+		return app.application.Delete(app.name)
+	case "migrate":
+		return app.application.Migrate(app.name)
+	case "ls":
+		return app.withContext(args, 0, 1, app.ls)
+	case "put":
+		return app.withContext(args, 2, 2, app.put)
+	case "get":
+		return app.withContext(args, 2, 2, app.get)
+	case "rm":
+		return app.withContext(args, 2, 2, app.rm)
+	case "log":
+		return app.withContext(args, 0, 0, app.log)
+	case "show":
+		return app.withContext(args, 1, 1, app.show)
+	case "verify":
+		return app.withContext(args, 0, 0, app.verify)
+	case "export":
+		return app.withContext(args, 1, 1, app.export)
+	case "train":
+		return app.withContext(args, 1, 1, app.train)
+	case "tag":
+		return app.withContext(args, 1, 2, app.tag)
+	case "tags":
+		return app.withContext(args, 0, 0, app.tags)
+	case "kind":
+		return app.withContext(args, 2, 3, app.kind)
+	case "kinds":
+		return app.withContext(args, 0, 0, app.kinds)
+	case "stats":
+		return app.withContext(args, 0, 0, app.stats)
+	case "watch":
+		return app.withContext(args, 0, 0, app.watch)
+	}
+
+	return fmt.Errorf("the command (%s) is not supported: %w", name, errUsage)
+}
+
+func (app *command) withContext(args []string, minArgs int, maxArgs int, fn func(context uint, args []string) error) error {
+	if len(args) < minArgs || len(args) > maxArgs {
+		return fmt.Errorf("%d arguments provided: %w", len(args), errUsage)
+	}
+
+	open := app.application.Open
+	if app.pTag != nil {
+		open = func(name string) (*uint, error) {
+			return app.application.OpenTag(name, *app.pTag)
+		}
+	}
+
+	pContext, err := open(app.name)
+	if err != nil {
+		return err
+	}
+
+	defer app.application.Close(*pContext)
+	return fn(*pContext, args)
+}
+
+func (app *command) ls(context uint, args []string) error {
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	if !reference.HasContentKeys() {
+		return nil
+	}
+
+	list := reference.ContentKeys().List()
+	if len(args) > 0 {
+		kind, err := app.parseKind(context, args[0])
+		if err != nil {
+			return err
+		}
+
+		list, err = reference.ContentKeys().ListByKind(kind)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, oneContentKey := range list {
+		fmt.Fprintf(app.output, "%s\t%s\t%d\n", app.kindName(reference, oneContentKey.Kind()), oneContentKey.Hash().String(), oneContentKey.Content().Length())
+	}
+
+	return nil
+}
+
+func (app *command) put(context uint, args []string) error {
+	kind, err := app.parseKind(context, args[0])
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if args[1] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pHash, err := app.hashAdapter.FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	content, err := app.contentBuilder.Create().
+		WithHash(*pHash).
+		WithKind(kind).
+		WithData(data).
+		Now()
+
+	if err != nil {
+		return err
+	}
+
+	err = app.commit(context, func() error {
+		return app.application.Insert(context, content)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(app.output, pHash.String())
+	return nil
+}
+
+func (app *command) get(context uint, args []string) error {
+	kind, pHash, err := app.parseKindAndHash(context, args)
+	if err != nil {
+		return err
+	}
+
+	content, err := app.application.Retrieve(context, kind, *pHash)
+	if err != nil {
+		return err
+	}
+
+	_, err = app.output.Write(content.Data())
+	return err
+}
+
+func (app *command) rm(context uint, args []string) error {
+	kind, pHash, err := app.parseKindAndHash(context, args)
+	if err != nil {
+		return err
+	}
+
+	return app.commit(context, func() error {
+		return app.application.Erase(context, kind, *pHash)
+	})
+}
+
+func (app *command) log(context uint, args []string) error {
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	list := reference.Commits().List()
+	for i := len(list) - 1; i >= 0; i-- {
+		app.printCommit(list[i])
+		fmt.Fprintln(app.output)
+	}
+
+	return nil
+}
+
+func (app *command) show(context uint, args []string) error {
+	pHash, err := app.hashAdapter.FromString(args[0])
+	if err != nil {
+		return err
+	}
+
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	commit, err := reference.Commits().Fetch(*pHash)
+	if err != nil {
+		return err
+	}
+
+	app.printCommit(commit)
+	if !reference.HasContentKeys() {
+		return nil
+	}
+
+	fmt.Fprintln(app.output, "contents:")
+	for _, oneContentKey := range reference.ContentKeys().List() {
+		if !oneContentKey.Commit().Compare(commit.Hash()) {
+			continue
+		}
+
+		fmt.Fprintf(app.output, "  %s\t%s\t%d\n", app.kindName(reference, oneContentKey.Kind()), oneContentKey.Hash().String(), oneContentKey.Content().Length())
+	}
+
+	return nil
+}
+
+func (app *command) verify(context uint, args []string) error {
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	var pParent *hash.Hash
+	for index, oneCommit := range reference.Commits().List() {
+		if index > 0 && (!oneCommit.HasParent() || !oneCommit.Parent().Compare(*pParent)) {
+			return fmt.Errorf("the commit (hash: %s) at index %d is not a child of its preceding commit", oneCommit.Hash().String(), index)
+		}
+
+		currentHash := oneCommit.Hash()
+		pParent = &currentHash
+	}
+
+	amount := 0
+	if reference.HasContentKeys() {
+		for _, oneContentKey := range reference.ContentKeys().List() {
+			content, err := app.application.Retrieve(context, oneContentKey.Kind(), oneContentKey.Hash())
+			if err != nil {
+				return err
+			}
+
+			pHash, err := app.hashAdapter.FromBytes(content.Data())
+			if err != nil {
+				return err
+			}
+
+			if !pHash.Compare(oneContentKey.Hash()) {
+				return fmt.Errorf("the content (kind: %d, hash: %s) does not match its data hash (%s)", oneContentKey.Kind(), oneContentKey.Hash().String(), pHash.String())
+			}
+
+			amount++
+		}
+	}
+
+	fmt.Fprintf(app.output, "ok: %d commits, %d contents\n", len(reference.Commits().List()), amount)
+	return nil
+}
+
+func (app *command) export(context uint, args []string) error {
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	if !reference.HasContentKeys() {
+		return nil
+	}
+
+	for _, oneContentKey := range reference.ContentKeys().List() {
+		content, err := app.application.Retrieve(context, oneContentKey.Kind(), oneContentKey.Hash())
+		if err != nil {
+			return err
+		}
+
+		dirPath := filepath.Join(args[0], strconv.Itoa(int(content.Kind())))
+		err = os.MkdirAll(dirPath, 0777)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dirPath, content.Hash().String())
+		err = os.WriteFile(path, content.Data(), 0666)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (app *command) train(context uint, args []string) error {
+	kind, err := app.parseKind(context, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = app.application.Lock(context)
+	if err != nil {
+		return err
+	}
+
+	defer app.application.Unlock(context)
+	return app.application.TrainDictionary(context, kind)
+}
+
+func (app *command) tag(context uint, args []string) error {
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	commit := reference.Commits().Latest().Hash()
+	if len(args) > 1 {
+		pHash, err := app.hashAdapter.FromString(args[1])
+		if err != nil {
+			return err
+		}
+
+		commit = *pHash
+	}
+
+	err = app.application.Lock(context)
+	if err != nil {
+		return err
+	}
+
+	defer app.application.Unlock(context)
+	return app.application.Tag(context, args[0], commit)
+}
+
+func (app *command) tags(context uint, args []string) error {
+	tags, err := app.application.ListTags(context)
+	if err != nil {
+		return err
+	}
+
+	for _, oneTag := range tags {
+		fmt.Fprintf(app.output, "%s\t%s\n", oneTag.Name(), oneTag.Commit().String())
+	}
+
+	return nil
+}
+
+func (app *command) kind(context uint, args []string) error {
+	number, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("the kind (%s) is invalid: %w", args[0], errUsage)
+	}
+
+	builder := references.NewKindBuilder().Create().
+		WithNumber(uint(number)).
+		WithName(args[1]).
+		WithSchema(app.schema)
+
+	if len(args) > 2 {
+		builder.WithDescription(args[2])
+	}
+
+	kind, err := builder.Now()
+	if err != nil {
+		return err
+	}
+
+	err = app.application.Lock(context)
+	if err != nil {
+		return err
+	}
+
+	defer app.application.Unlock(context)
+	return app.application.RegisterKind(context, kind)
+}
+
+func (app *command) kinds(context uint, args []string) error {
+	kinds, err := app.application.ListKinds(context)
+	if err != nil {
+		return err
+	}
+
+	for _, oneKind := range kinds {
+		fmt.Fprintf(app.output, "%d\t%s\t%s\t%s\n", oneKind.Number(), oneKind.Name(), oneKind.Schema(), oneKind.Description())
+	}
+
+	return nil
+}
+
+func (app *command) stats(context uint, args []string) error {
+	stats, err := app.application.Stats(context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.output, "file size         %d\n", stats.FileSize)
+	fmt.Fprintf(app.output, "header size       %d\n", stats.HeaderSize)
+	fmt.Fprintf(app.output, "data size         %d\n", stats.DataSize)
+	fmt.Fprintf(app.output, "live bytes        %d\n", stats.LiveBytes)
+	fmt.Fprintf(app.output, "dead bytes        %d\n", stats.DeadBytes)
+	fmt.Fprintf(app.output, "fragmentation     %.2f%%\n", stats.Fragmentation*100)
+	fmt.Fprintf(app.output, "commits           %d\n", stats.Commits)
+	fmt.Fprintf(app.output, "average content   %.2f\n", stats.AverageContentSize)
+
+	kinds := []uint{}
+	for oneKind := range stats.ContentsPerKind {
+		kinds = append(kinds, oneKind)
+	}
+
+	if len(kinds) <= 0 {
+		return nil
+	}
+
+	reference, err := app.application.Reference(context)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(kinds, func(i int, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	fmt.Fprintln(app.output, "contents:")
+	for _, oneKind := range kinds {
+		fmt.Fprintf(app.output, "  %s\t%d\n", app.kindName(reference, oneKind), stats.ContentsPerKind[oneKind])
+	}
+
+	return nil
+}
+
+// watch prints the commits written to the database, by any process, until interrupted
+func (app *command) watch(context uint, args []string) error {
+	events, cancelFn, err := app.application.Watch(app.name, watchInterval)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFn()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			// the context is refreshed once the commit is detected, so its reference contains the kinds registered since:
+			reference, err := app.application.Reference(context)
+			if err != nil {
+				return err
+			}
+
+			app.printCommit(event.Commit)
+			for _, oneContentKey := range event.Inserted {
+				fmt.Fprintf(app.output, "+ %s\t%s\n", app.kindName(reference, oneContentKey.Kind()), oneContentKey.Hash().String())
+			}
+
+			for _, oneContentKey := range event.Deleted {
+				fmt.Fprintf(app.output, "- %s\t%s\n", app.kindName(reference, oneContentKey.Kind()), oneContentKey.Hash().String())
+			}
+
+			fmt.Fprintln(app.output)
+		case <-interrupt:
+			return nil
+		}
+	}
+}
+
+func (app *command) commit(context uint, fn func() error) error {
+	err := app.application.Lock(context)
+	if err != nil {
+		return err
+	}
+
+	defer app.application.Unlock(context)
+	err = fn()
+	if err != nil {
+		return err
+	}
+
+	if app.metadata != nil {
+		return app.application.CommitWithMetadata(context, app.metadata)
+	}
+
+	return app.application.Commit(context)
+}
+
+func (app *command) printCommit(commit references.Commit) {
+	fmt.Fprintf(app.output, "commit %s\n", commit.Hash().String())
+	if commit.HasParent() {
+		fmt.Fprintf(app.output, "parent %s\n", commit.Parent().String())
+	}
+
+	if commit.HasSignature() {
+		fmt.Fprintf(app.output, "signer %s\n", hex.EncodeToString(commit.Signature().Author()))
+	}
+
+	if commit.HasMetadata() && commit.Metadata().Author() != "" {
+		fmt.Fprintf(app.output, "author %s\n", commit.Metadata().Author())
+	}
+
+	fmt.Fprintf(app.output, "date   %s\n", commit.CreatedOn().Format("2006-01-02 15:04:05.000000000 MST"))
+	if !commit.HasMetadata() {
+		return
+	}
+
+	metadata := commit.Metadata()
+	annotations := metadata.Annotations()
+	names := []string{}
+	for oneName := range annotations {
+		names = append(names, oneName)
+	}
+
+	sort.Strings(names)
+	for _, oneName := range names {
+		fmt.Fprintf(app.output, "%s: %s\n", oneName, annotations[oneName])
+	}
+
+	if metadata.Message() != "" {
+		fmt.Fprintln(app.output)
+		for _, oneLine := range strings.Split(metadata.Message(), "\n") {
+			fmt.Fprintf(app.output, "    %s\n", oneLine)
+		}
+	}
+}
+
+// parseKind parses a kind number, or the name of a registered kind
+func (app *command) parseKind(context uint, value string) (uint, error) {
+	kind, err := strconv.ParseUint(value, 10, 64)
+	if err == nil {
+		return uint(kind), nil
+	}
+
+	kinds, err := app.application.ListKinds(context)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, oneKind := range kinds {
+		if oneKind.Name() == value {
+			return oneKind.Number(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("the kind (%s) is invalid: %w", value, errUsage)
+}
+
+// kindName returns the name of the kind when it is registered, its number otherwise
+func (app *command) kindName(reference references.Reference, kind uint) string {
+	if reference.HasKinds() {
+		registered, err := reference.Kinds().Fetch(kind)
+		if err == nil {
+			return registered.Name()
+		}
+	}
+
+	return strconv.Itoa(int(kind))
+}
+
+func (app *command) parseKindAndHash(context uint, args []string) (uint, *hash.Hash, error) {
+	kind, err := app.parseKind(context, args[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pHash, err := app.hashAdapter.FromString(args[1])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return kind, pHash, nil
+}
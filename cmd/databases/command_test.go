@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/databases/infrastructure/files"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+func TestCommand_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	output := bytes.Buffer{}
+	cmd := newCommandForTests(t, dirPath, nil, nil, &output)
+	if cmd == nil {
+		return
+	}
+
+	if !executeForTests(t, cmd, "new") {
+		return
+	}
+
+	first := []byte("this is the first data")
+	pFirstHash := putForTests(t, cmd, &output, dirPath, "1", first)
+	if pFirstHash == nil {
+		return
+	}
+
+	// the kinds are registered once the database contains a commit:
+	if !executeForTests(t, cmd, "kind", "1", "documents", "the documents") {
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "kinds") {
+		return
+	}
+
+	if output.String() != "1\tdocuments\t\tthe documents\n" {
+		t.Errorf("the kinds output is invalid: %s", output.String())
+		return
+	}
+
+	second := []byte("this is the second data")
+	pSecondHash := putForTests(t, cmd, &output, dirPath, "0", second)
+	if pSecondHash == nil {
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "get", "documents", pFirstHash.String()) {
+		return
+	}
+
+	if !bytes.Equal(output.Bytes(), first) {
+		t.Errorf("the get output was expected to be the data of the content")
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "ls", "documents") {
+		return
+	}
+
+	expected := fmt.Sprintf("documents\t%s\t%d\n", pFirstHash.String(), len(first))
+	if output.String() != expected {
+		t.Errorf("the ls output was expected to be %s, %s returned", expected, output.String())
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "log") {
+		return
+	}
+
+	commits := strings.Split(strings.TrimSpace(output.String()), "\n\n")
+	if len(commits) != 2 || !strings.HasPrefix(commits[0], "commit ") || !strings.Contains(commits[0], "parent ") {
+		t.Errorf("the log output was expected to contain the two commits, latest first: %s", output.String())
+		return
+	}
+
+	latestCommit := strings.TrimPrefix(strings.SplitN(commits[0], "\n", 2)[0], "commit ")
+	output.Reset()
+	if !executeForTests(t, cmd, "show", latestCommit) {
+		return
+	}
+
+	expected = fmt.Sprintf("contents:\n  0\t%s\t%d\n", pSecondHash.String(), len(second))
+	if !strings.HasSuffix(output.String(), expected) {
+		t.Errorf("the show output was expected to end with the contents of the commit: %s", output.String())
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "verify") {
+		return
+	}
+
+	if output.String() != "ok: 2 commits, 2 contents\n" {
+		t.Errorf("the verify output is invalid: %s", output.String())
+		return
+	}
+
+	exportPath := filepath.Join(dirPath, "export")
+	if !executeForTests(t, cmd, "export", exportPath) {
+		return
+	}
+
+	exported, err := os.ReadFile(filepath.Join(exportPath, "1", pFirstHash.String()))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(exported, first) {
+		t.Errorf("the exported file was expected to contain the data of the content")
+		return
+	}
+
+	if !executeForTests(t, cmd, "tag", "v1") {
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "tags") {
+		return
+	}
+
+	if output.String() != fmt.Sprintf("v1\t%s\n", latestCommit) {
+		t.Errorf("the tags output is invalid: %s", output.String())
+		return
+	}
+
+	if !executeForTests(t, cmd, "rm", "documents", pFirstHash.String()) {
+		return
+	}
+
+	err = cmd.execute("get", []string{"documents", pFirstHash.String()})
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "stats") {
+		return
+	}
+
+	// the removed content is pinned by the tag:
+	if !strings.Contains(output.String(), "dead bytes        0\n") || !strings.Contains(output.String(), "commits           3\n") {
+		t.Errorf("the stats output is invalid: %s", output.String())
+		return
+	}
+
+	// the command opened on the tag reads the removed content:
+	tag := "v1"
+	tagOutput := bytes.Buffer{}
+	tagCmd := newCommandForTests(t, dirPath, &tag, nil, &tagOutput)
+	if tagCmd == nil {
+		return
+	}
+
+	if !executeForTests(t, tagCmd, "get", "documents", pFirstHash.String()) {
+		return
+	}
+
+	if !bytes.Equal(tagOutput.Bytes(), first) {
+		t.Errorf("the get output of the tag was expected to be the data of the removed content")
+		return
+	}
+
+	err = tagCmd.execute("rm", []string{"documents", pFirstHash.String()})
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	if !executeForTests(t, cmd, "delete") {
+		return
+	}
+
+	err = cmd.execute("ls", []string{})
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestCommand_withMetadata_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	metadata, err := references.NewMetadataBuilder().Create().
+		WithMessage("this is the first line\nthis is the second line").
+		WithAuthor("my_author").
+		WithAnnotations(map[string]string{"ticket": "123"}).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	output := bytes.Buffer{}
+	cmd := newCommandForTests(t, dirPath, nil, metadata, &output)
+	if cmd == nil {
+		return
+	}
+
+	if !executeForTests(t, cmd, "new") {
+		return
+	}
+
+	if putForTests(t, cmd, &output, dirPath, "0", []byte("this is some data")) == nil {
+		return
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "log") {
+		return
+	}
+
+	expected := "author my_author\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("the log output was expected to contain the author: %s", output.String())
+		return
+	}
+
+	expected = "ticket: 123\n\n    this is the first line\n    this is the second line\n"
+	if !strings.HasSuffix(output.String(), expected+"\n") {
+		t.Errorf("the log output was expected to end with the annotations and the message: %s", output.String())
+		return
+	}
+}
+
+func TestCommand_withInvalidArguments_returnsUsageError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	output := bytes.Buffer{}
+	cmd := newCommandForTests(t, dirPath, nil, nil, &output)
+	if cmd == nil {
+		return
+	}
+
+	if !executeForTests(t, cmd, "new") {
+		return
+	}
+
+	if putForTests(t, cmd, &output, dirPath, "0", []byte("this is some data")) == nil {
+		return
+	}
+
+	output.Reset()
+	invalids := [][]string{
+		{"unknown"},
+		{"get", "0"},
+		{"log", "extra"},
+		{"ls", "unregistered"},
+		{"kind", "invalid", "documents"},
+	}
+
+	for _, oneInvalid := range invalids {
+		err := cmd.execute(oneInvalid[0], oneInvalid[1:])
+		if !errors.Is(err, errUsage) {
+			t.Errorf("the command (%s) was expected to return errUsage, %v returned", strings.Join(oneInvalid, " "), err)
+			return
+		}
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("the invalid commands were expected to NOT write any output")
+		return
+	}
+}
+
+func newCommandForTests(t *testing.T, dirPath string, pTag *string, metadata references.Metadata, output *bytes.Buffer) *command {
+	application, err := files.NewApplicationBuilder().Create().
+		WithStorage(files.NewOSStorage()).
+		WithDirPath(dirPath).
+		WithDestinationExtension(dstExtension).
+		WithBackupExtension(bckExtension).
+		WithReadChunkSize(1000000).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	return createCommand(application, "my_name", pTag, "", metadata, output)
+}
+
+func executeForTests(t *testing.T, cmd *command, name string, args ...string) bool {
+	err := cmd.execute(name, args)
+	if err != nil {
+		t.Errorf("the command (%s) was expected to succeed, error returned: %s", name, err.Error())
+		return false
+	}
+
+	return true
+}
+
+func putForTests(t *testing.T, cmd *command, output *bytes.Buffer, dirPath string, kind string, data []byte) *hash.Hash {
+	path := filepath.Join(dirPath, "put")
+	err := os.WriteFile(path, data, 0666)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	output.Reset()
+	if !executeForTests(t, cmd, "put", kind, path) {
+		return nil
+	}
+
+	pHash, err := hash.NewAdapter().FromBytes(data)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	if output.String() != pHash.String()+"\n" {
+		t.Errorf("the put output was expected to be the hash of the content (%s), %s returned", pHash.String(), output.String())
+		return nil
+	}
+
+	return pHash
+}
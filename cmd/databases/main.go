@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+const dstExtension = "destination"
+const bckExtension = "backup"
+
+const usage = `usage: databases [flags] <command> [arguments]
+
+commands:
+  new                   creates the database
+  delete                deletes the database
+  migrate               upgrades the database from a previous format version
+  ls [kind]             lists the contents of the database
+  put <kind> <file>     inserts the content of a file ("-" for stdin) and commits
+  get <kind> <hash>     writes a content to stdout
+  rm <kind> <hash>      deletes a content and commits
+  log                   lists the commits, latest first
+  show <commit>         shows a commit and the contents it inserted
+  verify                verifies the contents hashes and the commits chain
+  export <directory>    writes every content to <directory>/<kind>/<hash>
+  train <kind>          trains a compression dictionary on the contents of a kind
+  tag <name> [commit]   tags a commit, the latest one by default
+  tags                  lists the tags
+  kind <number> <name> [description]
+                        registers the name, description and -schema of a kind
+  kinds                 lists the registered kinds
+  stats                 shows the sizes, live and dead bytes and contents per kind of the database
+  watch                 prints the commits written to the database, by any process, until interrupted
+
+encrypted databases require the -key flag on every command.
+commits are signed when the -sign flag is provided.
+the -message, -author and -annotation flags add metadata to the commits of put and rm.
+the -at flag opens the database, read-only, as it was at the commit of a tag.
+a kind argument is either a number or the name of a registered kind.
+
+flags:
+`
+
+func main() {
+	dirPath := flag.String("dir", ".", "the directory containing the databases")
+	name := flag.String("name", "", "the name of the database")
+	readChunkSize := flag.Uint("chunk", 1000000, "the amount of bytes read at once when copying a database")
+	keyPath := flag.String("key", "", "the path of a file containing the hex encoded AES key of an encrypted database")
+	signPath := flag.String("sign", "", "the path of a file containing the hex encoded ed25519 seed used to sign the commits")
+	schema := flag.String("schema", "", "the schema identifier of the kind registered by the kind command")
+	at := flag.String("at", "", "the tag the database is opened on, read-only")
+	message := flag.String("message", "", "the message of the commit")
+	author := flag.String("author", "", "the author of the commit")
+	annotations := annotationsFlag{}
+	flag.Var(annotations, "annotation", "an annotation of the commit, as key=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	if flag.NArg() <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "the name flag is mandatory")
+		os.Exit(2)
+	}
+
+	builder := files.NewApplicationBuilder().Create().
+		WithStorage(files.NewOSStorage()).
+		WithDirPath(*dirPath).
+		WithDestinationExtension(dstExtension).
+		WithBackupExtension(bckExtension).
+		WithReadChunkSize(*readChunkSize)
+
+	if *keyPath != "" {
+		key, err := readKey(*keyPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		builder.WithKeyProvider(files.NewKeyProvider(key))
+	}
+
+	if *signPath != "" {
+		seed, err := readKey(*signPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+
+		if len(seed) != ed25519.SeedSize {
+			fmt.Fprintf(os.Stderr, "the signing seed was expected to contain %d bytes, %d provided\n", ed25519.SeedSize, len(seed))
+			os.Exit(1)
+		}
+
+		builder.WithSigner(ed25519.NewKeyFromSeed(seed))
+	}
+
+	application, err := builder.Now()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	var metadata references.Metadata
+	if *message != "" || *author != "" || len(annotations) > 0 {
+		metadata, err = references.NewMetadataBuilder().Create().
+			WithMessage(*message).
+			WithAuthor(*author).
+			WithAnnotations(annotations).
+			Now()
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var pTag *string
+	if *at != "" {
+		pTag = at
+	}
+
+	cmd := createCommand(application, *name, pTag, *schema, metadata, os.Stdout)
+	err = cmd.execute(flag.Arg(0), flag.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			os.Exit(2)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// annotationsFlag collects the repeated key=value annotation flags
+type annotationsFlag map[string]string
+
+func (obj annotationsFlag) String() string {
+	return ""
+}
+
+func (obj annotationsFlag) Set(value string) error {
+	name, annotation, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("the annotation (%s) was expected to be formatted as key=value", value)
+	}
+
+	obj[name] = annotation
+	return nil
+}
+
+func readKey(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(content)))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAnnotationsFlag_Success(t *testing.T) {
+	annotations := annotationsFlag{}
+	for _, oneValue := range []string{"ticket=123", "reviewer=", "empty=a=b"} {
+		err := annotations.Set(oneValue)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+
+	expected := annotationsFlag{"ticket": "123", "reviewer": "", "empty": "a=b"}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("the annotations were expected to be %v, %v returned", expected, annotations)
+		return
+	}
+
+	for _, oneInvalid := range []string{"ticket", "=123"} {
+		err := annotations.Set(oneInvalid)
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+	}
+}
+
+func TestReadKey_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	err := os.MkdirAll(dirPath, 0777)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	path := filepath.Join(dirPath, "key")
+	err = os.WriteFile(path, []byte("00010203\n"), 0666)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	key, err := readKey(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(key, []byte{0, 1, 2, 3}) {
+		t.Errorf("the key was expected to be decoded from its hex encoding")
+		return
+	}
+
+	err = os.WriteFile(path, []byte("not hex"), 0666)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = readKey(path)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	_, err = readKey(filepath.Join(dirPath, "absent"))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
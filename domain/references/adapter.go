@@ -9,17 +9,26 @@ import (
 type adapter struct {
 	contentKeysAdapter ContentKeysAdapter
 	commitsAdapter     CommitsAdapter
+	resourcesAdapter   ResourcesAdapter
+	tagsAdapter        TagsAdapter
+	kindsAdapter       KindsAdapter
 	builder            Builder
 }
 
 func createAdapter(
 	contentKeysAdapter ContentKeysAdapter,
 	commitsAdapter CommitsAdapter,
+	resourcesAdapter ResourcesAdapter,
+	tagsAdapter TagsAdapter,
+	kindsAdapter KindsAdapter,
 	builder Builder,
 ) Adapter {
 	out := adapter{
 		contentKeysAdapter: contentKeysAdapter,
 		commitsAdapter:     commitsAdapter,
+		resourcesAdapter:   resourcesAdapter,
+		tagsAdapter:        tagsAdapter,
+		kindsAdapter:       kindsAdapter,
 		builder:            builder,
 	}
 	return &out
@@ -40,10 +49,14 @@ func (app *adapter) ToContent(ins Reference) ([]byte, error) {
 	output = append(output, commitLengthBytes...)
 	output = append(output, commitsBytes...)
 
-	if ins.HasContentKeys() {
-		contentKeyBytes, err := app.contentKeysAdapter.ToContent(ins.ContentKeys())
-		if err != nil {
-			return nil, err
+	// the content keys section is written empty when only the resources, tags or kinds are present:
+	if ins.HasContentKeys() || ins.HasResources() || ins.HasTags() || ins.HasKinds() {
+		contentKeyBytes := []byte{}
+		if ins.HasContentKeys() {
+			contentKeyBytes, err = app.contentKeysAdapter.ToContent(ins.ContentKeys())
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		contentKeysLengthBytes := make([]byte, 8)
@@ -53,6 +66,53 @@ func (app *adapter) ToContent(ins Reference) ([]byte, error) {
 		output = append(output, contentKeyBytes...)
 	}
 
+	// the resources section is written empty when only the tags or kinds are present:
+	if ins.HasResources() || ins.HasTags() || ins.HasKinds() {
+		resourcesBytes := []byte{}
+		if ins.HasResources() {
+			resourcesBytes, err = app.resourcesAdapter.ToContent(ins.Resources())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resourcesLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(resourcesLengthBytes, uint64(len(resourcesBytes)))
+
+		output = append(output, resourcesLengthBytes...)
+		output = append(output, resourcesBytes...)
+	}
+
+	// the tags section is written empty when only the kinds are present:
+	if ins.HasTags() || ins.HasKinds() {
+		tagsBytes := []byte{}
+		if ins.HasTags() {
+			tagsBytes, err = app.tagsAdapter.ToContent(ins.Tags())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tagsLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(tagsLengthBytes, uint64(len(tagsBytes)))
+
+		output = append(output, tagsLengthBytes...)
+		output = append(output, tagsBytes...)
+	}
+
+	if ins.HasKinds() {
+		kindsBytes, err := app.kindsAdapter.ToContent(ins.Kinds())
+		if err != nil {
+			return nil, err
+		}
+
+		kindsLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(kindsLengthBytes, uint64(len(kindsBytes)))
+
+		output = append(output, kindsLengthBytes...)
+		output = append(output, kindsBytes...)
+	}
+
 	return output, nil
 }
 
@@ -80,21 +140,90 @@ func (app *adapter) ToReference(content []byte) (Reference, error) {
 	remaining := content[commitBytesDelimiter:]
 	builder := app.builder.Create().WithCommits(commits)
 	if len(remaining) > 0 {
-		contentKeysBytesLengthDelimiter := uint64(8)
-		contentKeysBytesLength := binary.LittleEndian.Uint64(remaining[:contentKeysBytesLengthDelimiter])
-		contentKeysBytesDelimiter := int(contentKeysBytesLength + contentKeysBytesLengthDelimiter)
-		if contentLength < contentKeysBytesDelimiter {
-			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the ContentKeys size of the Reference instance, %d provided", contentKeysBytesDelimiter, contentLength)
-			return nil, errors.New(str)
+		section, next, err := app.section(remaining, "ContentKeys")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(section) > 0 {
+			contentKeys, err := app.contentKeysAdapter.ToContentKeys(section)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WithContentKeys(contentKeys)
 		}
 
-		contentKeys, err := app.contentKeysAdapter.ToContentKeys(remaining[contentKeysBytesLengthDelimiter:contentKeysBytesDelimiter])
+		remaining = next
+	}
+
+	if len(remaining) > 0 {
+		section, next, err := app.section(remaining, "Resources")
 		if err != nil {
 			return nil, err
 		}
 
-		builder.WithContentKeys(contentKeys)
+		if len(section) > 0 {
+			resources, err := app.resourcesAdapter.ToResources(section)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WithResources(resources)
+		}
+
+		remaining = next
+	}
+
+	if len(remaining) > 0 {
+		section, next, err := app.section(remaining, "Tags")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(section) > 0 {
+			tags, err := app.tagsAdapter.ToTags(section)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WithTags(tags)
+		}
+
+		remaining = next
+	}
+
+	if len(remaining) > 0 {
+		section, _, err := app.section(remaining, "Kinds")
+		if err != nil {
+			return nil, err
+		}
+
+		kinds, err := app.kindsAdapter.ToKinds(section)
+		if err != nil {
+			return nil, err
+		}
+
+		builder.WithKinds(kinds)
 	}
 
 	return builder.Now()
 }
+
+// section returns the length-prefixed section at the beginning of the content, then the remaining bytes
+func (app *adapter) section(content []byte, name string) ([]byte, []byte, error) {
+	lengthDelimiter := uint64(8)
+	contentLength := uint64(len(content))
+	if contentLength < lengthDelimiter {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the %s size of the Reference instance, %d provided", lengthDelimiter, name, contentLength)
+		return nil, nil, errors.New(str)
+	}
+
+	delimiter := lengthDelimiter + binary.LittleEndian.Uint64(content[:lengthDelimiter])
+	if contentLength < delimiter {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the %s of the Reference instance, %d provided", delimiter, name, contentLength)
+		return nil, nil, errors.New(str)
+	}
+
+	return content[lengthDelimiter:delimiter], content[delimiter:], nil
+}
@@ -46,3 +46,86 @@ func TestAdapter_withContentKeys_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestAdapter_withResources_Success(t *testing.T) {
+	references := []Reference{
+		NewReferenceWithResourcesForTests(false),
+		NewReferenceWithResourcesForTests(true),
+	}
+
+	adapter := NewAdapter()
+	for _, oneReference := range references {
+		content, err := adapter.ToContent(oneReference)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		retReference, err := adapter.ToReference(content)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneReference, retReference) {
+			t.Errorf("the returned reference is invalid")
+			return
+		}
+	}
+}
+
+func TestAdapter_withTags_Success(t *testing.T) {
+	references := []Reference{
+		NewReferenceWithTagsForTests(false, false),
+		NewReferenceWithTagsForTests(true, false),
+		NewReferenceWithTagsForTests(false, true),
+		NewReferenceWithTagsForTests(true, true),
+	}
+
+	adapter := NewAdapter()
+	for _, oneReference := range references {
+		content, err := adapter.ToContent(oneReference)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		retReference, err := adapter.ToReference(content)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneReference, retReference) {
+			t.Errorf("the returned reference is invalid")
+			return
+		}
+	}
+}
+
+func TestAdapter_withKinds_Success(t *testing.T) {
+	references := []Reference{
+		NewReferenceWithKindsForTests(false),
+		NewReferenceWithKindsForTests(true),
+	}
+
+	adapter := NewAdapter()
+	for _, oneReference := range references {
+		content, err := adapter.ToContent(oneReference)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		retReference, err := adapter.ToReference(content)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneReference, retReference) {
+			t.Errorf("the returned reference is invalid")
+			return
+		}
+	}
+}
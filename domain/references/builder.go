@@ -7,12 +7,18 @@ import (
 type builder struct {
 	contentKeys ContentKeys
 	commits     Commits
+	resources   Resources
+	tags        Tags
+	kinds       Kinds
 }
 
 func createBuilder() Builder {
 	out := builder{
 		contentKeys: nil,
 		commits:     nil,
+		resources:   nil,
+		tags:        nil,
+		kinds:       nil,
 	}
 
 	return &out
@@ -35,15 +41,45 @@ func (app *builder) WithCommits(commits Commits) Builder {
 	return app
 }
 
+// WithResources add resources to the builder
+func (app *builder) WithResources(resources Resources) Builder {
+	app.resources = resources
+	return app
+}
+
+// WithTags add tags to the builder
+func (app *builder) WithTags(tags Tags) Builder {
+	app.tags = tags
+	return app
+}
+
+// WithKinds add kinds to the builder
+func (app *builder) WithKinds(kinds Kinds) Builder {
+	app.kinds = kinds
+	return app
+}
+
 // Now builds a new Reference instance
 func (app *builder) Now() (Reference, error) {
 	if app.commits == nil {
 		return nil, errors.New("the Commits is mandatory in order to build a Reference instance")
 	}
 
+	if app.tags != nil || app.kinds != nil {
+		return createReferenceInternally(app.commits, app.contentKeys, app.resources, app.tags, app.kinds), nil
+	}
+
+	if app.contentKeys != nil && app.resources != nil {
+		return createReferenceWithContentKeysAndResources(app.commits, app.contentKeys, app.resources), nil
+	}
+
 	if app.contentKeys != nil {
 		return createReferenceWithContentKeys(app.commits, app.contentKeys), nil
 	}
 
+	if app.resources != nil {
+		return createReferenceWithResources(app.commits, app.resources), nil
+	}
+
 	return createReference(app.commits), nil
 }
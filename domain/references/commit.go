@@ -11,6 +11,8 @@ type commit struct {
 	action    Action
 	createdOn time.Time
 	pParent   *hash.Hash
+	signature Signature
+	metadata  Metadata
 }
 
 func createCommit(
@@ -18,7 +20,7 @@ func createCommit(
 	action Action,
 	createdOn time.Time,
 ) Commit {
-	return createCommitInternally(hash, action, createdOn, nil)
+	return createCommitInternally(hash, action, createdOn, nil, nil, nil)
 }
 
 func createCommitWithParent(
@@ -27,7 +29,7 @@ func createCommitWithParent(
 	createdOn time.Time,
 	pParent *hash.Hash,
 ) Commit {
-	return createCommitInternally(hash, action, createdOn, pParent)
+	return createCommitInternally(hash, action, createdOn, pParent, nil, nil)
 }
 
 func createCommitInternally(
@@ -35,12 +37,16 @@ func createCommitInternally(
 	action Action,
 	createdOn time.Time,
 	pParent *hash.Hash,
+	signature Signature,
+	metadata Metadata,
 ) Commit {
 	out := commit{
 		hash:      hash,
 		action:    action,
 		createdOn: createdOn,
 		pParent:   pParent,
+		signature: signature,
+		metadata:  metadata,
 	}
 
 	return &out
@@ -70,3 +76,23 @@ func (obj *commit) HasParent() bool {
 func (obj *commit) Parent() *hash.Hash {
 	return obj.pParent
 }
+
+// HasSignature returns true if there is a signature, false otherwise
+func (obj *commit) HasSignature() bool {
+	return obj.signature != nil
+}
+
+// Signature returns the signature, if any
+func (obj *commit) Signature() Signature {
+	return obj.signature
+}
+
+// HasMetadata returns true if there is metadata, false otherwise
+func (obj *commit) HasMetadata() bool {
+	return obj.metadata != nil
+}
+
+// Metadata returns the metadata, if any
+func (obj *commit) Metadata() Metadata {
+	return obj.metadata
+}
@@ -1,6 +1,7 @@
 package references
 
 import (
+	"crypto/ed25519"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -10,20 +11,23 @@ import (
 )
 
 type commitAdapter struct {
-	hashAdapter   hash.Adapter
-	actionAdapter ActionAdapter
-	builder       CommitBuilder
+	hashAdapter     hash.Adapter
+	actionAdapter   ActionAdapter
+	metadataAdapter MetadataAdapter
+	builder         CommitBuilder
 }
 
 func createCommitAdapter(
 	hashAdapter hash.Adapter,
 	actionAdapter ActionAdapter,
+	metadataAdapter MetadataAdapter,
 	builder CommitBuilder,
 ) CommitAdapter {
 	out := commitAdapter{
-		hashAdapter:   hashAdapter,
-		actionAdapter: actionAdapter,
-		builder:       builder,
+		hashAdapter:     hashAdapter,
+		actionAdapter:   actionAdapter,
+		metadataAdapter: metadataAdapter,
+		builder:         builder,
 	}
 
 	return &out
@@ -48,10 +52,28 @@ func (app *commitAdapter) ToContent(ins Commit) ([]byte, error) {
 	if ins.HasParent() {
 		parentHashBytes := ins.Parent().Bytes()
 
-		output = append(output, 1)
+		output = append(output, commitParentFlag)
 		output = append(output, parentHashBytes...)
 	}
 
+	if ins.HasMetadata() {
+		metadataBytes, err := app.metadataAdapter.ToContent(ins.Metadata())
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, commitMetadataFlag)
+		output = appendLengthPrefixed(output, metadataBytes)
+	}
+
+	if ins.HasSignature() {
+		signature := ins.Signature()
+
+		output = append(output, commitSignatureFlag)
+		output = append(output, signature.Author()...)
+		output = append(output, signature.Data()...)
+	}
+
 	return output, nil
 }
 
@@ -78,14 +100,52 @@ func (app *commitAdapter) ToCommit(content []byte) (Commit, error) {
 
 	remaining := content[actionBytesDelimiter:]
 	builder := app.builder.Create().WithAction(action).CreatedOn(createdOn)
-	if len(remaining) > 0 {
-		pParentHash, err := app.hashAdapter.FromBytes(remaining[1:])
-		if err != nil {
-			return nil, err
+	for len(remaining) > 0 {
+		flag := remaining[0]
+		remaining = remaining[1:]
+		switch flag {
+		case commitParentFlag:
+			if len(remaining) < hash.Size {
+				str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the parent of the Commit instance, %d provided", hash.Size, len(remaining))
+				return nil, errors.New(str)
+			}
+
+			pParentHash, err := app.hashAdapter.FromBytes(remaining[:hash.Size])
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WithParent(*pParentHash)
+			remaining = remaining[hash.Size:]
+		case commitMetadataFlag:
+			metadataBytes, next, err := readLengthPrefixed(remaining)
+			if err != nil {
+				return nil, err
+			}
+
+			metadata, err := app.metadataAdapter.ToMetadata(metadataBytes)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WithMetadata(metadata)
+			remaining = next
+		case commitSignatureFlag:
+			if len(remaining) < commitSignatureSize {
+				str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the signature of the Commit instance, %d provided", commitSignatureSize, len(remaining))
+				return nil, errors.New(str)
+			}
+
+			author := ed25519.PublicKey(remaining[:ed25519.PublicKeySize])
+			signature := remaining[ed25519.PublicKeySize:commitSignatureSize]
+			builder.WithSignature(author, signature)
+			remaining = remaining[commitSignatureSize:]
+		default:
+			str := fmt.Sprintf("the Commit instance contains an invalid flag: %d", flag)
+			return nil, errors.New(str)
 		}
-
-		builder.WithParent(*pParentHash)
 	}
 
+	// the signature, if any, is verified while building the commit:
 	return builder.Now()
 }
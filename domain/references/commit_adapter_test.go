@@ -46,3 +46,81 @@ func TestCommitAdapter_withParent_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestCommitAdapter_withParent_withSigner_Success(t *testing.T) {
+	commit := NewCommitWithParentAndSignerForTests(NewSignerForTests("this is a seed"))
+	adapter := NewCommitAdapter()
+	content, err := adapter.ToContent(commit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retCommit, err := adapter.ToCommit(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(commit, retCommit) {
+		t.Errorf("the returned commit is invalid")
+		return
+	}
+}
+
+func TestCommitAdapter_withTamperedSignature_returnsError(t *testing.T) {
+	commit := NewCommitWithParentAndSignerForTests(NewSignerForTests("this is a seed"))
+	adapter := NewCommitAdapter()
+	content, err := adapter.ToContent(commit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	content[len(content)-1]++
+	_, err = adapter.ToCommit(content)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestCommitAdapter_withMetadata_Success(t *testing.T) {
+	commit := NewCommitWithMetadataForTests(NewMetadataForTests())
+	adapter := NewCommitAdapter()
+	content, err := adapter.ToContent(commit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retCommit, err := adapter.ToCommit(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(commit, retCommit) {
+		t.Errorf("the returned commit is invalid")
+		return
+	}
+}
+
+func TestCommitBuilder_withMetadata_changesHash(t *testing.T) {
+	commit := NewCommitWithMetadataForTests(NewMetadataForTests())
+	other, err := NewCommitBuilder().Create().
+		WithAction(commit.Action()).
+		WithParent(*commit.Parent()).
+		CreatedOn(commit.CreatedOn()).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if commit.Hash().Compare(other.Hash()) {
+		t.Errorf("the metadata was expected to be part of the commit hash")
+		return
+	}
+}
@@ -1,6 +1,9 @@
 package references
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"time"
@@ -13,6 +16,10 @@ type commitBuilder struct {
 	action      Action
 	pParent     *hash.Hash
 	pCreatedOn  *time.Time
+	signer      crypto.Signer
+	author      ed25519.PublicKey
+	signature   []byte
+	metadata    Metadata
 }
 
 func createCommitBuilder(
@@ -23,6 +30,10 @@ func createCommitBuilder(
 		action:      nil,
 		pParent:     nil,
 		pCreatedOn:  nil,
+		signer:      nil,
+		author:      nil,
+		signature:   nil,
+		metadata:    nil,
 	}
 
 	return &out
@@ -53,6 +64,25 @@ func (app *commitBuilder) CreatedOn(createdOn time.Time) CommitBuilder {
 	return app
 }
 
+// WithMetadata adds metadata to the builder
+func (app *commitBuilder) WithMetadata(metadata Metadata) CommitBuilder {
+	app.metadata = metadata
+	return app
+}
+
+// WithSigner adds an ed25519 signer to the builder, the commit hash is then signed
+func (app *commitBuilder) WithSigner(signer crypto.Signer) CommitBuilder {
+	app.signer = signer
+	return app
+}
+
+// WithSignature adds the signature of an author to the builder, the signature is then verified against the commit hash
+func (app *commitBuilder) WithSignature(author ed25519.PublicKey, signature []byte) CommitBuilder {
+	app.author = author
+	app.signature = signature
+	return app
+}
+
 // Now builds a new Commit instance
 func (app *commitBuilder) Now() (Commit, error) {
 	if app.action == nil {
@@ -72,14 +102,64 @@ func (app *commitBuilder) Now() (Commit, error) {
 		data = append(data, app.pParent.Bytes())
 	}
 
+	if app.metadata != nil {
+		data = append(data, []byte(metadataHashPrefix), app.metadata.Hash().Bytes())
+	}
+
 	pHash, err := app.hashAdapter.FromMultiBytes(data)
 	if err != nil {
 		return nil, err
 	}
 
+	signature, err := app.sign(*pHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if signature != nil || app.metadata != nil {
+		return createCommitInternally(*pHash, app.action, *app.pCreatedOn, app.pParent, signature, app.metadata), nil
+	}
+
 	if app.pParent != nil {
 		return createCommitWithParent(*pHash, app.action, *app.pCreatedOn, app.pParent), nil
 	}
 
 	return createCommit(*pHash, app.action, *app.pCreatedOn), nil
 }
+
+// sign signs the hash using the signer, or verifies the provided signature, if any
+func (app *commitBuilder) sign(hash hash.Hash) (Signature, error) {
+	if app.signer != nil && app.signature != nil {
+		return nil, errors.New("the signer and the signature cannot be both provided in order to build a Commit instance")
+	}
+
+	if app.signer != nil {
+		author, ok := app.signer.Public().(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("the signer was expected to contain an ed25519 public key in order to build a Commit instance")
+		}
+
+		data, err := app.signer.Sign(rand.Reader, hash.Bytes(), crypto.Hash(0))
+		if err != nil {
+			return nil, err
+		}
+
+		return createSignature(author, data), nil
+	}
+
+	if app.signature == nil {
+		return nil, nil
+	}
+
+	if len(app.author) != ed25519.PublicKeySize {
+		str := fmt.Sprintf("the author was expected to contain %d bytes in order to build a Commit instance, %d provided", ed25519.PublicKeySize, len(app.author))
+		return nil, errors.New(str)
+	}
+
+	if !ed25519.Verify(app.author, hash.Bytes(), app.signature) {
+		str := fmt.Sprintf("the signature of the commit (hash: %s) is invalid", hash.String())
+		return nil, errors.New(str)
+	}
+
+	return createSignature(app.author, app.signature), nil
+}
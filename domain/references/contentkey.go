@@ -9,6 +9,8 @@ type contentKey struct {
 	kind    uint
 	content Pointer
 	commit  hash.Hash
+	codec   uint
+	pKey    *uint
 }
 
 func createContentKey(
@@ -16,12 +18,37 @@ func createContentKey(
 	kind uint,
 	content Pointer,
 	commit hash.Hash,
+	codec uint,
+) ContentKey {
+	return createContentKeyInternally(hash, kind, content, commit, codec, nil)
+}
+
+func createContentKeyWithKey(
+	hash hash.Hash,
+	kind uint,
+	content Pointer,
+	commit hash.Hash,
+	codec uint,
+	pKey *uint,
+) ContentKey {
+	return createContentKeyInternally(hash, kind, content, commit, codec, pKey)
+}
+
+func createContentKeyInternally(
+	hash hash.Hash,
+	kind uint,
+	content Pointer,
+	commit hash.Hash,
+	codec uint,
+	pKey *uint,
 ) ContentKey {
 	out := contentKey{
 		hash:    hash,
 		kind:    kind,
 		content: content,
 		commit:  commit,
+		codec:   codec,
+		pKey:    pKey,
 	}
 
 	return &out
@@ -46,3 +73,18 @@ func (obj *contentKey) Content() Pointer {
 func (obj *contentKey) Commit() hash.Hash {
 	return obj.commit
 }
+
+// Codec returns the identifier of the codec the content is compressed with
+func (obj *contentKey) Codec() uint {
+	return obj.codec
+}
+
+// HasKey returns true if the content is encrypted, false otherwise
+func (obj *contentKey) HasKey() bool {
+	return obj.pKey != nil
+}
+
+// Key returns the identifier of the key the content is encrypted with, if any
+func (obj *contentKey) Key() *uint {
+	return obj.pKey
+}
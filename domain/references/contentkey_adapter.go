@@ -12,17 +12,42 @@ type contentKeyAdapter struct {
 	hashAdapter    hash.Adapter
 	pointerAdapter PointerAdapter
 	builder        ContentKeyBuilder
+	pointerSize    int
+	size           int
+	hasEncoding    bool
 }
 
 func createContentKeyAdapter(
 	hashAdapter hash.Adapter,
 	pointerAdapter PointerAdapter,
 	builder ContentKeyBuilder,
+) ContentKeyAdapter {
+	return createContentKeyAdapterInternally(hashAdapter, pointerAdapter, builder, pointerSize, contentKeySize, true)
+}
+
+func createOriginalContentKeyAdapter(
+	hashAdapter hash.Adapter,
+	pointerAdapter PointerAdapter,
+	builder ContentKeyBuilder,
+) ContentKeyAdapter {
+	return createContentKeyAdapterInternally(hashAdapter, pointerAdapter, builder, originalPointerSize, originalContentKeySize, false)
+}
+
+func createContentKeyAdapterInternally(
+	hashAdapter hash.Adapter,
+	pointerAdapter PointerAdapter,
+	builder ContentKeyBuilder,
+	pointerSize int,
+	size int,
+	hasEncoding bool,
 ) ContentKeyAdapter {
 	out := contentKeyAdapter{
 		hashAdapter:    hashAdapter,
 		pointerAdapter: pointerAdapter,
 		builder:        builder,
+		pointerSize:    pointerSize,
+		size:           size,
+		hasEncoding:    hasEncoding,
 	}
 
 	return &out
@@ -41,19 +66,35 @@ func (app *contentKeyAdapter) ToContent(ins ContentKey) ([]byte, error) {
 	}
 
 	commitBytes := ins.Commit().Bytes()
-
 	output := []byte{}
 	output = append(output, hashBytes...)
 	output = append(output, kindBytes...)
 	output = append(output, contentBytes...)
 	output = append(output, commitBytes...)
+	if !app.hasEncoding {
+		return output, nil
+	}
+
+	codecBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(codecBytes, uint64(ins.Codec()))
+
+	keyFlag := byte(0)
+	keyBytes := make([]byte, 8)
+	if ins.HasKey() {
+		keyFlag = 1
+		binary.LittleEndian.PutUint64(keyBytes, uint64(*ins.Key()))
+	}
+
+	output = append(output, codecBytes...)
+	output = append(output, keyFlag)
+	output = append(output, keyBytes...)
 	return output, nil
 }
 
 // ToContentKey converts bytes to ContentKey instance
 func (app *contentKeyAdapter) ToContentKey(content []byte) (ContentKey, error) {
-	if len(content) != contentKeySize {
-		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to a Pointer instance, %d provided", contentKeySize, len(content))
+	if len(content) != app.size {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to a Pointer instance, %d provided", app.size, len(content))
 		return nil, errors.New(str)
 	}
 
@@ -65,7 +106,7 @@ func (app *contentKeyAdapter) ToContentKey(content []byte) (ContentKey, error) {
 	kindDelimiter := hash.Size + 8
 	kind := binary.LittleEndian.Uint64(content[hash.Size:kindDelimiter])
 
-	contentDelimiter := kindDelimiter + pointerSize
+	contentDelimiter := kindDelimiter + app.pointerSize
 	pointerContent, err := app.pointerAdapter.ToPointer(content[kindDelimiter:contentDelimiter])
 	if err != nil {
 		return nil, err
@@ -77,10 +118,25 @@ func (app *contentKeyAdapter) ToContentKey(content []byte) (ContentKey, error) {
 		return nil, err
 	}
 
-	return app.builder.Create().
+	builder := app.builder.Create().
 		WithHash(*pHash).
 		WithKind(uint(kind)).
 		WithContent(pointerContent).
-		WithCommit(*pCommitHash).
-		Now()
+		WithCommit(*pCommitHash)
+
+	if !app.hasEncoding {
+		return builder.Now()
+	}
+
+	codecDelimiter := commitDelimiter + 8
+	codec := binary.LittleEndian.Uint64(content[commitDelimiter:codecDelimiter])
+	builder.WithCodec(uint(codec))
+
+	keyFlagDelimiter := codecDelimiter + 1
+	if content[codecDelimiter] == 1 {
+		key := binary.LittleEndian.Uint64(content[keyFlagDelimiter : keyFlagDelimiter+8])
+		builder.WithKey(uint(key))
+	}
+
+	return builder.Now()
 }
@@ -25,3 +25,24 @@ func TestContentKeyAdapter_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestContentKeyAdapter_withCodec_withKey_Success(t *testing.T) {
+	contentKey := NewContentKeyWithCodecAndKeyForTests(2, 45)
+	adapter := NewContentKeyAdapter()
+	content, err := adapter.ToContent(contentKey)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retContentKey, err := adapter.ToContentKey(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(contentKey, retContentKey) {
+		t.Errorf("the returned contentKey is invalid")
+		return
+	}
+}
@@ -11,6 +11,8 @@ type contentKeyBuilder struct {
 	pKind   *uint
 	content Pointer
 	pCommit *hash.Hash
+	codec   uint
+	pKey    *uint
 }
 
 func createContentKeyBuilder() ContentKeyBuilder {
@@ -19,6 +21,8 @@ func createContentKeyBuilder() ContentKeyBuilder {
 		pKind:   nil,
 		content: nil,
 		pCommit: nil,
+		codec:   0,
+		pKey:    nil,
 	}
 
 	return &out
@@ -53,6 +57,18 @@ func (app *contentKeyBuilder) WithCommit(commit hash.Hash) ContentKeyBuilder {
 	return app
 }
 
+// WithCodec adds the identifier of the compression codec to the builder
+func (app *contentKeyBuilder) WithCodec(codec uint) ContentKeyBuilder {
+	app.codec = codec
+	return app
+}
+
+// WithKey adds the identifier of the encryption key to the builder
+func (app *contentKeyBuilder) WithKey(key uint) ContentKeyBuilder {
+	app.pKey = &key
+	return app
+}
+
 // Now builds a new ContentKey instance
 func (app *contentKeyBuilder) Now() (ContentKey, error) {
 	if app.pHash == nil {
@@ -71,5 +87,9 @@ func (app *contentKeyBuilder) Now() (ContentKey, error) {
 		return nil, errors.New("the commit is mandatory in order to build a ContentKey instance")
 	}
 
-	return createContentKey(*app.pHash, *app.pKind, app.content, *app.pCommit), nil
+	if app.pKey != nil {
+		return createContentKeyWithKey(*app.pHash, *app.pKind, app.content, *app.pCommit, app.codec, app.pKey), nil
+	}
+
+	return createContentKey(*app.pHash, *app.pKind, app.content, *app.pCommit, app.codec), nil
 }
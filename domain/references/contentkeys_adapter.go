@@ -7,17 +7,34 @@ import (
 )
 
 type contentKeysAdapter struct {
-	adapter ContentKeyAdapter
-	builder ContentKeysBuilder
+	adapter        ContentKeyAdapter
+	builder        ContentKeysBuilder
+	contentKeySize int
 }
 
 func createContentKeysAdapter(
 	adapter ContentKeyAdapter,
 	builder ContentKeysBuilder,
+) ContentKeysAdapter {
+	return createContentKeysAdapterInternally(adapter, builder, contentKeySize)
+}
+
+func createOriginalContentKeysAdapter(
+	adapter ContentKeyAdapter,
+	builder ContentKeysBuilder,
+) ContentKeysAdapter {
+	return createContentKeysAdapterInternally(adapter, builder, originalContentKeySize)
+}
+
+func createContentKeysAdapterInternally(
+	adapter ContentKeyAdapter,
+	builder ContentKeysBuilder,
+	contentKeySize int,
 ) ContentKeysAdapter {
 	out := contentKeysAdapter{
-		adapter: adapter,
-		builder: builder,
+		adapter:        adapter,
+		builder:        builder,
+		contentKeySize: contentKeySize,
 	}
 
 	return &out
@@ -46,17 +63,23 @@ func (app *contentKeysAdapter) ToContent(ins ContentKeys) ([]byte, error) {
 
 // ToContentKeys converts bytes to ContentKeys
 func (app *contentKeysAdapter) ToContentKeys(content []byte) (ContentKeys, error) {
-	smallest := 8 + contentKeySize
+	smallest := 8 + app.contentKeySize
 	if len(content) < smallest {
 		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a ContentKey instance, %d provided", smallest, len(content))
 		return nil, errors.New(str)
 	}
 
+	length := binary.LittleEndian.Uint64(content[:8])
+	expected := 8 + (length * uint64(app.contentKeySize))
+	if length > uint64(len(content)/app.contentKeySize) || uint64(len(content)) != expected {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to %d ContentKey instances, %d provided", expected, length, len(content))
+		return nil, errors.New(str)
+	}
+
 	list := []ContentKey{}
-	length := int(binary.LittleEndian.Uint64(content[:8]))
-	for i := 0; i < length; i++ {
-		beginsOn := 8 + (i * contentKeySize)
-		endsOn := beginsOn + contentKeySize
+	for i := 0; i < int(length); i++ {
+		beginsOn := 8 + (i * app.contentKeySize)
+		endsOn := beginsOn + app.contentKeySize
 		ins, err := app.adapter.ToContentKey(content[beginsOn:endsOn])
 		if err != nil {
 			return nil, err
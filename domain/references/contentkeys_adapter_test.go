@@ -38,3 +38,65 @@ func TestContentKeysAdapter_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestContentKeysAdapter_withOriginalAdapter_Success(t *testing.T) {
+	list := []ContentKey{
+		NewContentKeyForTests(),
+		NewContentKeyForTests(),
+	}
+
+	contentKeys, err := NewContentKeysBuilder().Create().WithList(list).Now()
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	adapter := NewOriginalContentKeysAdapter()
+	content, err := adapter.ToContent(contentKeys)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	expected := 8 + len(list)*originalContentKeySize
+	if len(content) != expected {
+		t.Errorf("the content was expected to contain %d bytes, %d returned", expected, len(content))
+		return
+	}
+
+	retContentKeys, err := adapter.ToContentKeys(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(contentKeys, retContentKeys) {
+		t.Errorf("the returned contentKeys is invalid")
+		return
+	}
+}
+
+func TestContentKeysAdapter_withOtherLayout_returnsError(t *testing.T) {
+	contentKeys, err := NewContentKeysBuilder().Create().WithList([]ContentKey{
+		NewContentKeyForTests(),
+		NewContentKeyForTests(),
+		NewContentKeyForTests(),
+	}).Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	content, err := NewOriginalContentKeysAdapter().ToContent(contentKeys)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = NewContentKeysAdapter().ToContentKeys(content)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
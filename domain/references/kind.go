@@ -0,0 +1,66 @@
+package references
+
+type kind struct {
+	number      uint
+	name        string
+	description string
+	schema      string
+}
+
+func createKind(
+	number uint,
+	name string,
+	description string,
+) Kind {
+	return createKindInternally(number, name, description, "")
+}
+
+func createKindWithSchema(
+	number uint,
+	name string,
+	description string,
+	schema string,
+) Kind {
+	return createKindInternally(number, name, description, schema)
+}
+
+func createKindInternally(
+	number uint,
+	name string,
+	description string,
+	schema string,
+) Kind {
+	out := kind{
+		number:      number,
+		name:        name,
+		description: description,
+		schema:      schema,
+	}
+
+	return &out
+}
+
+// Number returns the number
+func (obj *kind) Number() uint {
+	return obj.number
+}
+
+// Name returns the name
+func (obj *kind) Name() string {
+	return obj.name
+}
+
+// Description returns the description
+func (obj *kind) Description() string {
+	return obj.description
+}
+
+// HasSchema returns true if there is a schema, false otherwise
+func (obj *kind) HasSchema() bool {
+	return obj.schema != ""
+}
+
+// Schema returns the schema identifier, if any
+func (obj *kind) Schema() string {
+	return obj.schema
+}
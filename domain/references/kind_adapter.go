@@ -0,0 +1,71 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type kindAdapter struct {
+	builder KindBuilder
+}
+
+func createKindAdapter(
+	builder KindBuilder,
+) KindAdapter {
+	out := kindAdapter{
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts a Kind instance to bytes
+func (app *kindAdapter) ToContent(ins Kind) ([]byte, error) {
+	numberBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(numberBytes, uint64(ins.Number()))
+
+	output := []byte{}
+	output = append(output, numberBytes...)
+	output = appendLengthPrefixed(output, []byte(ins.Name()))
+	output = appendLengthPrefixed(output, []byte(ins.Description()))
+	output = appendLengthPrefixed(output, []byte(ins.Schema()))
+	return output, nil
+}
+
+// ToKind converts bytes to a Kind instance
+func (app *kindAdapter) ToKind(content []byte) (Kind, error) {
+	contentLength := len(content)
+	if contentLength < kindMinSize {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Kind instance, %d provided", kindMinSize, contentLength)
+		return nil, errors.New(str)
+	}
+
+	number := binary.LittleEndian.Uint64(content[:8])
+	name, remaining, err := readLengthPrefixed(content[8:])
+	if err != nil {
+		return nil, err
+	}
+
+	description, remaining, err := readLengthPrefixed(remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, remaining, err := readLengthPrefixed(remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(remaining) > 0 {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to a Kind instance, %d provided", contentLength-len(remaining), contentLength)
+		return nil, errors.New(str)
+	}
+
+	return app.builder.Create().
+		WithNumber(uint(number)).
+		WithName(string(name)).
+		WithDescription(string(description)).
+		WithSchema(string(schema)).
+		Now()
+}
@@ -0,0 +1,63 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKindAdapter_Success(t *testing.T) {
+	kinds := []Kind{
+		NewKindForTests(0, false),
+		NewKindForTests(1, true),
+	}
+
+	adapter := NewKindAdapter()
+	for _, oneKind := range kinds {
+		content, err := adapter.ToContent(oneKind)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		retKind, err := adapter.ToKind(content)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneKind, retKind) {
+			t.Errorf("the returned kind is invalid")
+			return
+		}
+	}
+}
+
+func TestKindAdapter_withInvalidLength_returnsError(t *testing.T) {
+	adapter := NewKindAdapter()
+	content, err := adapter.ToContent(NewKindForTests(0, true))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = adapter.ToKind(content[:len(content)-1])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestKindsBuilder_withDuplicatedName_returnsError(t *testing.T) {
+	first := NewKindForTests(0, false)
+	second, err := NewKindBuilder().Create().WithNumber(1).WithName(first.Name()).Now()
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = NewKindsBuilder().Create().WithList([]Kind{first, second}).Now()
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
@@ -0,0 +1,75 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type kindBuilder struct {
+	pNumber     *uint
+	name        string
+	description string
+	schema      string
+}
+
+func createKindBuilder() KindBuilder {
+	out := kindBuilder{
+		pNumber:     nil,
+		name:        "",
+		description: "",
+		schema:      "",
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *kindBuilder) Create() KindBuilder {
+	return createKindBuilder()
+}
+
+// WithNumber adds a number to the builder
+func (app *kindBuilder) WithNumber(number uint) KindBuilder {
+	app.pNumber = &number
+	return app
+}
+
+// WithName adds a name to the builder
+func (app *kindBuilder) WithName(name string) KindBuilder {
+	app.name = name
+	return app
+}
+
+// WithDescription adds a description to the builder
+func (app *kindBuilder) WithDescription(description string) KindBuilder {
+	app.description = description
+	return app
+}
+
+// WithSchema adds a schema identifier to the builder
+func (app *kindBuilder) WithSchema(schema string) KindBuilder {
+	app.schema = schema
+	return app
+}
+
+// Now builds a new Kind instance
+func (app *kindBuilder) Now() (Kind, error) {
+	if app.pNumber == nil {
+		return nil, errors.New("the number is mandatory in order to build a Kind instance")
+	}
+
+	if app.name == "" {
+		return nil, errors.New("the name is mandatory in order to build a Kind instance")
+	}
+
+	if len(app.description) > kindMaxDescriptionLength {
+		str := fmt.Sprintf("the description was expected to contain at most %d bytes, %d provided", kindMaxDescriptionLength, len(app.description))
+		return nil, errors.New(str)
+	}
+
+	if app.schema != "" {
+		return createKindWithSchema(*app.pNumber, app.name, app.description, app.schema), nil
+	}
+
+	return createKind(*app.pNumber, app.name, app.description), nil
+}
@@ -0,0 +1,51 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type kinds struct {
+	mp       map[uint]Kind
+	mpByName map[string]Kind
+	list     []Kind
+}
+
+func createKinds(
+	mp map[uint]Kind,
+	mpByName map[string]Kind,
+	list []Kind,
+) Kinds {
+	out := kinds{
+		mp:       mp,
+		mpByName: mpByName,
+		list:     list,
+	}
+
+	return &out
+}
+
+// List returns the kinds
+func (obj *kinds) List() []Kind {
+	return obj.list
+}
+
+// Fetch fetches a kind by number
+func (obj *kinds) Fetch(number uint) (Kind, error) {
+	if ins, ok := obj.mp[number]; ok {
+		return ins, nil
+	}
+
+	str := fmt.Sprintf("the kind (number: %d) is not registered", number)
+	return nil, errors.New(str)
+}
+
+// FetchByName fetches a kind by name
+func (obj *kinds) FetchByName(name string) (Kind, error) {
+	if ins, ok := obj.mpByName[name]; ok {
+		return ins, nil
+	}
+
+	str := fmt.Sprintf("the kind (name: %s) is not registered", name)
+	return nil, errors.New(str)
+}
@@ -0,0 +1,83 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type kindsAdapter struct {
+	adapter KindAdapter
+	builder KindsBuilder
+}
+
+func createKindsAdapter(
+	adapter KindAdapter,
+	builder KindsBuilder,
+) KindsAdapter {
+	out := kindsAdapter{
+		adapter: adapter,
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts Kinds to bytes
+func (app *kindsAdapter) ToContent(ins Kinds) ([]byte, error) {
+	list := ins.List()
+	lengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lengthBytes, uint64(len(list)))
+
+	output := []byte{}
+	output = append(output, lengthBytes...)
+	for _, oneKind := range list {
+		content, err := app.adapter.ToContent(oneKind)
+		if err != nil {
+			return nil, err
+		}
+
+		contentLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(contentLengthBytes, uint64(len(content)))
+		output = append(output, contentLengthBytes...)
+		output = append(output, content...)
+	}
+
+	return output, nil
+}
+
+// ToKinds converts bytes to Kinds
+func (app *kindsAdapter) ToKinds(content []byte) (Kinds, error) {
+	contentLength := uint64(len(content))
+	if contentLength < 8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Kinds instance, %d provided", 8, contentLength)
+		return nil, errors.New(str)
+	}
+
+	list := []Kind{}
+	length := binary.LittleEndian.Uint64(content[:8])
+	beginsOn := uint64(8)
+	for i := uint64(0); i < length; i++ {
+		if contentLength < beginsOn+8 {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the size of the Kind (index: %d), %d provided", beginsOn+8, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		dataBeginsOn := beginsOn + 8
+		endsOn := dataBeginsOn + binary.LittleEndian.Uint64(content[beginsOn:dataBeginsOn])
+		if contentLength < endsOn {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the Kind (index: %d), %d provided", endsOn, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		ins, err := app.adapter.ToKind(content[dataBeginsOn:endsOn])
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, ins)
+		beginsOn = endsOn
+	}
+
+	return app.builder.Create().WithList(list).Now()
+}
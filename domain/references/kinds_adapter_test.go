@@ -0,0 +1,27 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKindsAdapter_Success(t *testing.T) {
+	kinds := NewKindsForTests(5)
+	adapter := NewKindsAdapter()
+	content, err := adapter.ToContent(kinds)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retKinds, err := adapter.ToKinds(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(kinds, retKinds) {
+		t.Errorf("the returned kinds is invalid")
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type kindsBuilder struct {
+	list []Kind
+}
+
+func createKindsBuilder() KindsBuilder {
+	out := kindsBuilder{
+		list: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *kindsBuilder) Create() KindsBuilder {
+	return createKindsBuilder()
+}
+
+// WithList adds a list of kinds to the builder
+func (app *kindsBuilder) WithList(list []Kind) KindsBuilder {
+	app.list = list
+	return app
+}
+
+// Now builds a new Kinds instance
+func (app *kindsBuilder) Now() (Kinds, error) {
+	if app.list != nil && len(app.list) <= 0 {
+		app.list = nil
+	}
+
+	if app.list == nil {
+		return nil, errors.New("there must be at least 1 Kind in order to build a Kinds instance")
+	}
+
+	mp := map[uint]Kind{}
+	mpByName := map[string]Kind{}
+	for _, oneKind := range app.list {
+		number := oneKind.Number()
+		if _, ok := mp[number]; ok {
+			str := fmt.Sprintf("the kind (number: %d) is duplicated", number)
+			return nil, errors.New(str)
+		}
+
+		name := oneKind.Name()
+		if _, ok := mpByName[name]; ok {
+			str := fmt.Sprintf("the kind (name: %s) is duplicated", name)
+			return nil, errors.New(str)
+		}
+
+		mp[number] = oneKind
+		mpByName[name] = oneKind
+	}
+
+	return createKinds(mp, mpByName, app.list), nil
+}
@@ -0,0 +1,46 @@
+package references
+
+import "github.com/steve-care-software/libs/cryptography/hash"
+
+type metadata struct {
+	hash        hash.Hash
+	message     string
+	author      string
+	annotations map[string]string
+}
+
+func createMetadata(
+	hash hash.Hash,
+	message string,
+	author string,
+	annotations map[string]string,
+) Metadata {
+	out := metadata{
+		hash:        hash,
+		message:     message,
+		author:      author,
+		annotations: annotations,
+	}
+
+	return &out
+}
+
+// Hash returns the hash
+func (obj *metadata) Hash() hash.Hash {
+	return obj.hash
+}
+
+// Message returns the message
+func (obj *metadata) Message() string {
+	return obj.message
+}
+
+// Author returns the author
+func (obj *metadata) Author() string {
+	return obj.author
+}
+
+// Annotations returns the annotations
+func (obj *metadata) Annotations() map[string]string {
+	return obj.annotations
+}
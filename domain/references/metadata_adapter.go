@@ -0,0 +1,108 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type metadataAdapter struct {
+	builder MetadataBuilder
+}
+
+func createMetadataAdapter(
+	builder MetadataBuilder,
+) MetadataAdapter {
+	out := metadataAdapter{
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts a Metadata instance to bytes
+func (app *metadataAdapter) ToContent(ins Metadata) ([]byte, error) {
+	annotations := ins.Annotations()
+	amountBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(amountBytes, uint64(len(annotations)))
+
+	output := []byte{}
+	output = appendLengthPrefixed(output, []byte(ins.Message()))
+	output = appendLengthPrefixed(output, []byte(ins.Author()))
+	output = append(output, amountBytes...)
+	for _, oneName := range sortedAnnotationNames(annotations) {
+		output = appendLengthPrefixed(output, []byte(oneName))
+		output = appendLengthPrefixed(output, []byte(annotations[oneName]))
+	}
+
+	return output, nil
+}
+
+// ToMetadata converts bytes to a Metadata instance
+func (app *metadataAdapter) ToMetadata(content []byte) (Metadata, error) {
+	message, remaining, err := readLengthPrefixed(content)
+	if err != nil {
+		return nil, err
+	}
+
+	author, remaining, err := readLengthPrefixed(remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(remaining) < 8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the amount of annotations of the Metadata instance, %d provided", 8, len(remaining))
+		return nil, errors.New(str)
+	}
+
+	amount := binary.LittleEndian.Uint64(remaining[:8])
+	remaining = remaining[8:]
+	annotations := map[string]string{}
+	for i := uint64(0); i < amount; i++ {
+		name, next, err := readLengthPrefixed(remaining)
+		if err != nil {
+			return nil, err
+		}
+
+		value, next, err := readLengthPrefixed(next)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations[string(name)] = string(value)
+		remaining = next
+	}
+
+	if len(remaining) > 0 {
+		str := fmt.Sprintf("the content contains %d remaining bytes after the Metadata instance", len(remaining))
+		return nil, errors.New(str)
+	}
+
+	return app.builder.Create().
+		WithMessage(string(message)).
+		WithAuthor(string(author)).
+		WithAnnotations(annotations).
+		Now()
+}
+
+func appendLengthPrefixed(output []byte, data []byte) []byte {
+	lengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lengthBytes, uint64(len(data)))
+	output = append(output, lengthBytes...)
+	return append(output, data...)
+}
+
+func readLengthPrefixed(content []byte) ([]byte, []byte, error) {
+	if len(content) < 8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve a length, %d provided", 8, len(content))
+		return nil, nil, errors.New(str)
+	}
+
+	delimiter := 8 + binary.LittleEndian.Uint64(content[:8])
+	if uint64(len(content)) < delimiter {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes, %d provided", delimiter, len(content))
+		return nil, nil, errors.New(str)
+	}
+
+	return content[8:delimiter], content[delimiter:], nil
+}
@@ -0,0 +1,85 @@
+package references
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetadataAdapter_Success(t *testing.T) {
+	metadata := NewMetadataForTests()
+	adapter := NewMetadataAdapter()
+	content, err := adapter.ToContent(metadata)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retMetadata, err := adapter.ToMetadata(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(metadata, retMetadata) {
+		t.Errorf("the returned metadata is invalid")
+		return
+	}
+}
+
+func TestMetadataAdapter_withMessageOnly_Success(t *testing.T) {
+	metadata, err := NewMetadataBuilder().Create().WithMessage("this is a message").Now()
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	adapter := NewMetadataAdapter()
+	content, err := adapter.ToContent(metadata)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retMetadata, err := adapter.ToMetadata(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(metadata, retMetadata) {
+		t.Errorf("the returned metadata is invalid")
+		return
+	}
+}
+
+func TestMetadataAdapter_withInvalidLength_returnsError(t *testing.T) {
+	adapter := NewMetadataAdapter()
+	content, err := adapter.ToContent(NewMetadataForTests())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = adapter.ToMetadata(content[:len(content)-1])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestMetadataBuilder_isEmpty_returnsError(t *testing.T) {
+	_, err := NewMetadataBuilder().Create().Now()
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestMetadataBuilder_withTooLongMessage_returnsError(t *testing.T) {
+	_, err := NewMetadataBuilder().Create().WithMessage(strings.Repeat("a", metadataMaxMessageLength+1)).Now()
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
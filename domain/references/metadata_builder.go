@@ -0,0 +1,111 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+type metadataBuilder struct {
+	hashAdapter hash.Adapter
+	message     string
+	author      string
+	annotations map[string]string
+}
+
+func createMetadataBuilder(
+	hashAdapter hash.Adapter,
+) MetadataBuilder {
+	out := metadataBuilder{
+		hashAdapter: hashAdapter,
+		message:     "",
+		author:      "",
+		annotations: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *metadataBuilder) Create() MetadataBuilder {
+	return createMetadataBuilder(
+		app.hashAdapter,
+	)
+}
+
+// WithMessage adds a message to the builder
+func (app *metadataBuilder) WithMessage(message string) MetadataBuilder {
+	app.message = message
+	return app
+}
+
+// WithAuthor adds an author to the builder
+func (app *metadataBuilder) WithAuthor(author string) MetadataBuilder {
+	app.author = author
+	return app
+}
+
+// WithAnnotations adds annotations to the builder
+func (app *metadataBuilder) WithAnnotations(annotations map[string]string) MetadataBuilder {
+	app.annotations = annotations
+	return app
+}
+
+// Now builds a new Metadata instance
+func (app *metadataBuilder) Now() (Metadata, error) {
+	if app.annotations == nil {
+		app.annotations = map[string]string{}
+	}
+
+	if app.message == "" && app.author == "" && len(app.annotations) <= 0 {
+		return nil, errors.New("the message, author or annotations is mandatory in order to build a Metadata instance")
+	}
+
+	if len(app.message) > metadataMaxMessageLength {
+		str := fmt.Sprintf("the message was expected to contain at most %d bytes, %d provided", metadataMaxMessageLength, len(app.message))
+		return nil, errors.New(str)
+	}
+
+	if len(app.annotations) > metadataMaxAnnotations {
+		str := fmt.Sprintf("the Metadata instance was expected to contain at most %d annotations, %d provided", metadataMaxAnnotations, len(app.annotations))
+		return nil, errors.New(str)
+	}
+
+	data := [][]byte{
+		[]byte(app.message),
+		[]byte(app.author),
+	}
+
+	for _, oneName := range sortedAnnotationNames(app.annotations) {
+		if oneName == "" {
+			return nil, errors.New("the annotations cannot contain an empty name in order to build a Metadata instance")
+		}
+
+		data = append(data, []byte(oneName), []byte(app.annotations[oneName]))
+	}
+
+	// length-prefix every field so that different fields cannot produce the same hash:
+	prefixed := [][]byte{}
+	for _, oneData := range data {
+		prefixed = append(prefixed, []byte(fmt.Sprintf("%d:", len(oneData))), oneData)
+	}
+
+	pHash, err := app.hashAdapter.FromMultiBytes(prefixed)
+	if err != nil {
+		return nil, err
+	}
+
+	return createMetadata(*pHash, app.message, app.author, app.annotations), nil
+}
+
+func sortedAnnotationNames(annotations map[string]string) []string {
+	names := []string{}
+	for oneName := range annotations {
+		names = append(names, oneName)
+	}
+
+	sort.Strings(names)
+	return names
+}
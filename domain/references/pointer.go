@@ -1,17 +1,35 @@
 package references
 
 type pointer struct {
-	from   uint
-	length uint
+	from      uint
+	length    uint
+	pChecksum *uint32
 }
 
 func createPointer(
 	from uint,
 	length uint,
+) Pointer {
+	return createPointerInternally(from, length, nil)
+}
+
+func createPointerWithChecksum(
+	from uint,
+	length uint,
+	pChecksum *uint32,
+) Pointer {
+	return createPointerInternally(from, length, pChecksum)
+}
+
+func createPointerInternally(
+	from uint,
+	length uint,
+	pChecksum *uint32,
 ) Pointer {
 	out := pointer{
-		from:   from,
-		length: length,
+		from:      from,
+		length:    length,
+		pChecksum: pChecksum,
 	}
 
 	return &out
@@ -26,3 +44,13 @@ func (obj *pointer) From() uint {
 func (obj *pointer) Length() uint {
 	return obj.length
 }
+
+// HasChecksum returns true if there is a checksum, false otherwise
+func (obj *pointer) HasChecksum() bool {
+	return obj.pChecksum != nil
+}
+
+// Checksum returns the checksum, if any
+func (obj *pointer) Checksum() *uint32 {
+	return obj.pChecksum
+}
@@ -7,14 +7,29 @@ import (
 )
 
 type pointerAdapter struct {
-	builder PointerBuilder
+	builder    PointerBuilder
+	isOriginal bool
 }
 
 func createPointerAdapter(
 	builder PointerBuilder,
+) PointerAdapter {
+	return createPointerAdapterInternally(builder, false)
+}
+
+func createOriginalPointerAdapter(
+	builder PointerBuilder,
+) PointerAdapter {
+	return createPointerAdapterInternally(builder, true)
+}
+
+func createPointerAdapterInternally(
+	builder PointerBuilder,
+	isOriginal bool,
 ) PointerAdapter {
 	out := pointerAdapter{
-		builder: builder,
+		builder:    builder,
+		isOriginal: isOriginal,
 	}
 
 	return &out
@@ -31,20 +46,44 @@ func (app *pointerAdapter) ToContent(ins Pointer) ([]byte, error) {
 	output := []byte{}
 	output = append(output, []byte(fromBytes)...)
 	output = append(output, []byte(lengthBytes)...)
+	if app.isOriginal {
+		return output, nil
+	}
+
+	checksumFlag := byte(0)
+	checksumBytes := make([]byte, 4)
+	if ins.HasChecksum() {
+		checksumFlag = 1
+		binary.LittleEndian.PutUint32(checksumBytes, *ins.Checksum())
+	}
+
+	output = append(output, checksumFlag)
+	output = append(output, checksumBytes...)
 	return output, nil
 }
 
 // ToPointer converts bytes to pointer
 func (app *pointerAdapter) ToPointer(content []byte) (Pointer, error) {
-	if len(content) != pointerSize {
-		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to a Pointer instance, %d provided", pointerSize, len(content))
+	expected := pointerSize
+	if app.isOriginal {
+		expected = originalPointerSize
+	}
+
+	if len(content) != expected {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to convert to a Pointer instance, %d provided", expected, len(content))
 		return nil, errors.New(str)
 	}
 
 	from := binary.LittleEndian.Uint64(content[:8])
-	length := binary.LittleEndian.Uint64(content[8:])
-	return app.builder.Create().
+	length := binary.LittleEndian.Uint64(content[8:originalPointerSize])
+	builder := app.builder.Create().
 		From(uint(from)).
-		WithLength(uint(length)).
-		Now()
+		WithLength(uint(length))
+
+	if !app.isOriginal && content[originalPointerSize] == 1 {
+		checksum := binary.LittleEndian.Uint32(content[originalPointerSize+1:])
+		builder.WithChecksum(checksum)
+	}
+
+	return builder.Now()
 }
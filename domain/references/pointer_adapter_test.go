@@ -25,3 +25,38 @@ func TestPointerAdapter_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestPointerAdapter_withChecksum_Success(t *testing.T) {
+	pointer := NewPointerWithChecksumForTests(3735928559)
+	adapter := NewPointerAdapter()
+	content, err := adapter.ToContent(pointer)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retPointer, err := adapter.ToPointer(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !retPointer.HasChecksum() {
+		t.Errorf("the pointer was expected to contain a checksum")
+		return
+	}
+
+	if !reflect.DeepEqual(pointer, retPointer) {
+		t.Errorf("the returned pointer is invalid")
+		return
+	}
+}
+
+func TestPointerAdapter_withOriginalContent_ReturnsError(t *testing.T) {
+	content := make([]byte, originalPointerSize)
+	_, err := NewPointerAdapter().ToPointer(content)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
@@ -3,14 +3,16 @@ package references
 import "errors"
 
 type pointerBuilder struct {
-	pFrom  *uint
-	length uint
+	pFrom     *uint
+	length    uint
+	pChecksum *uint32
 }
 
 func createPointerBuilder() PointerBuilder {
 	out := pointerBuilder{
-		pFrom:  nil,
-		length: 0,
+		pFrom:     nil,
+		length:    0,
+		pChecksum: nil,
 	}
 
 	return &out
@@ -33,6 +35,12 @@ func (app *pointerBuilder) From(from uint) PointerBuilder {
 	return app
 }
 
+// WithChecksum adds a checksum to the builder
+func (app *pointerBuilder) WithChecksum(checksum uint32) PointerBuilder {
+	app.pChecksum = &checksum
+	return app
+}
+
 // Now builds a new Pointer instance
 func (app *pointerBuilder) Now() (Pointer, error) {
 	if app.pFrom == nil {
@@ -43,5 +51,9 @@ func (app *pointerBuilder) Now() (Pointer, error) {
 		return nil, errors.New("the length must be greater than zero (0) in order to build a Pointer instance")
 	}
 
+	if app.pChecksum != nil {
+		return createPointerWithChecksum(*app.pFrom, app.length, app.pChecksum), nil
+	}
+
 	return createPointer(*app.pFrom, app.length), nil
 }
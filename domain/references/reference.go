@@ -3,28 +3,52 @@ package references
 type reference struct {
 	commits     Commits
 	contentKeys ContentKeys
+	resources   Resources
+	tags        Tags
+	kinds       Kinds
 }
 
 func createReference(
 	commits Commits,
 ) Reference {
-	return createReferenceInternally(commits, nil)
+	return createReferenceInternally(commits, nil, nil, nil, nil)
 }
 
 func createReferenceWithContentKeys(
 	commits Commits,
 	contentKeys ContentKeys,
 ) Reference {
-	return createReferenceInternally(commits, contentKeys)
+	return createReferenceInternally(commits, contentKeys, nil, nil, nil)
+}
+
+func createReferenceWithResources(
+	commits Commits,
+	resources Resources,
+) Reference {
+	return createReferenceInternally(commits, nil, resources, nil, nil)
+}
+
+func createReferenceWithContentKeysAndResources(
+	commits Commits,
+	contentKeys ContentKeys,
+	resources Resources,
+) Reference {
+	return createReferenceInternally(commits, contentKeys, resources, nil, nil)
 }
 
 func createReferenceInternally(
 	commits Commits,
 	contentKeys ContentKeys,
+	resources Resources,
+	tags Tags,
+	kinds Kinds,
 ) Reference {
 	out := reference{
 		contentKeys: contentKeys,
 		commits:     commits,
+		resources:   resources,
+		tags:        tags,
+		kinds:       kinds,
 	}
 
 	return &out
@@ -44,3 +68,33 @@ func (obj *reference) HasContentKeys() bool {
 func (obj *reference) ContentKeys() ContentKeys {
 	return obj.contentKeys
 }
+
+// HasResources returns true if there is resources, false otherwise
+func (obj *reference) HasResources() bool {
+	return obj.resources != nil
+}
+
+// Resources returns the resources
+func (obj *reference) Resources() Resources {
+	return obj.resources
+}
+
+// HasTags returns true if there is tags, false otherwise
+func (obj *reference) HasTags() bool {
+	return obj.tags != nil
+}
+
+// Tags returns the tags
+func (obj *reference) Tags() Tags {
+	return obj.tags
+}
+
+// HasKinds returns true if there is kinds, false otherwise
+func (obj *reference) HasKinds() bool {
+	return obj.kinds != nil
+}
+
+// Kinds returns the kinds
+func (obj *reference) Kinds() Kinds {
+	return obj.kinds
+}
@@ -0,0 +1,28 @@
+package references
+
+type resource struct {
+	name string
+	data []byte
+}
+
+func createResource(
+	name string,
+	data []byte,
+) Resource {
+	out := resource{
+		name: name,
+		data: data,
+	}
+
+	return &out
+}
+
+// Name returns the name
+func (obj *resource) Name() string {
+	return obj.name
+}
+
+// Data returns the data
+func (obj *resource) Data() []byte {
+	return obj.data
+}
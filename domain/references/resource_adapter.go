@@ -0,0 +1,66 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type resourceAdapter struct {
+	builder ResourceBuilder
+}
+
+func createResourceAdapter(
+	builder ResourceBuilder,
+) ResourceAdapter {
+	out := resourceAdapter{
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts a Resource instance to bytes
+func (app *resourceAdapter) ToContent(ins Resource) ([]byte, error) {
+	name := []byte(ins.Name())
+	nameLengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nameLengthBytes, uint64(len(name)))
+
+	data := ins.Data()
+	dataLengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(dataLengthBytes, uint64(len(data)))
+
+	output := []byte{}
+	output = append(output, nameLengthBytes...)
+	output = append(output, name...)
+	output = append(output, dataLengthBytes...)
+	output = append(output, data...)
+	return output, nil
+}
+
+// ToResource converts bytes to a Resource instance
+func (app *resourceAdapter) ToResource(content []byte) (Resource, error) {
+	contentLength := uint64(len(content))
+	if contentLength < resourceMinSize {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Resource instance, %d provided", resourceMinSize, contentLength)
+		return nil, errors.New(str)
+	}
+
+	nameDelimiter := 8 + binary.LittleEndian.Uint64(content[:8])
+	if contentLength < nameDelimiter+8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the name of the Resource instance, %d provided", nameDelimiter+8, contentLength)
+		return nil, errors.New(str)
+	}
+
+	dataLengthDelimiter := nameDelimiter + 8
+	dataDelimiter := dataLengthDelimiter + binary.LittleEndian.Uint64(content[nameDelimiter:dataLengthDelimiter])
+	if contentLength != dataDelimiter {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to retrieve the data of the Resource instance, %d provided", dataDelimiter, contentLength)
+		return nil, errors.New(str)
+	}
+
+	return app.builder.Create().
+		WithName(string(content[8:nameDelimiter])).
+		WithData(content[dataLengthDelimiter:dataDelimiter]).
+		Now()
+}
@@ -0,0 +1,43 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceAdapter_Success(t *testing.T) {
+	resource := NewResourceForTests("dictionary.0.1")
+	adapter := NewResourceAdapter()
+	content, err := adapter.ToContent(resource)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retResource, err := adapter.ToResource(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(resource, retResource) {
+		t.Errorf("the returned resource is invalid")
+		return
+	}
+}
+
+func TestResourceAdapter_withInvalidLength_returnsError(t *testing.T) {
+	resource := NewResourceForTests("dictionary.0.1")
+	adapter := NewResourceAdapter()
+	content, err := adapter.ToContent(resource)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = adapter.ToResource(content[:len(content)-1])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
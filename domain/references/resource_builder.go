@@ -0,0 +1,51 @@
+package references
+
+import "errors"
+
+type resourceBuilder struct {
+	name string
+	data []byte
+}
+
+func createResourceBuilder() ResourceBuilder {
+	out := resourceBuilder{
+		name: "",
+		data: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *resourceBuilder) Create() ResourceBuilder {
+	return createResourceBuilder()
+}
+
+// WithName adds a name to the builder
+func (app *resourceBuilder) WithName(name string) ResourceBuilder {
+	app.name = name
+	return app
+}
+
+// WithData adds data to the builder
+func (app *resourceBuilder) WithData(data []byte) ResourceBuilder {
+	app.data = data
+	return app
+}
+
+// Now builds a new Resource instance
+func (app *resourceBuilder) Now() (Resource, error) {
+	if app.name == "" {
+		return nil, errors.New("the name is mandatory in order to build a Resource instance")
+	}
+
+	if app.data != nil && len(app.data) <= 0 {
+		app.data = nil
+	}
+
+	if app.data == nil {
+		return nil, errors.New("the data is mandatory in order to build a Resource instance")
+	}
+
+	return createResource(app.name, app.data), nil
+}
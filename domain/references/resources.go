@@ -0,0 +1,38 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type resources struct {
+	mp   map[string]Resource
+	list []Resource
+}
+
+func createResources(
+	mp map[string]Resource,
+	list []Resource,
+) Resources {
+	out := resources{
+		mp:   mp,
+		list: list,
+	}
+
+	return &out
+}
+
+// List returns the resources
+func (obj *resources) List() []Resource {
+	return obj.list
+}
+
+// Fetch fetches a resource by name
+func (obj *resources) Fetch(name string) (Resource, error) {
+	if ins, ok := obj.mp[name]; ok {
+		return ins, nil
+	}
+
+	str := fmt.Sprintf("the resource (name: %s) does not exists", name)
+	return nil, errors.New(str)
+}
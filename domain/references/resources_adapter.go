@@ -0,0 +1,83 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type resourcesAdapter struct {
+	adapter ResourceAdapter
+	builder ResourcesBuilder
+}
+
+func createResourcesAdapter(
+	adapter ResourceAdapter,
+	builder ResourcesBuilder,
+) ResourcesAdapter {
+	out := resourcesAdapter{
+		adapter: adapter,
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts Resources to bytes
+func (app *resourcesAdapter) ToContent(ins Resources) ([]byte, error) {
+	list := ins.List()
+	lengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lengthBytes, uint64(len(list)))
+
+	output := []byte{}
+	output = append(output, lengthBytes...)
+	for _, oneResource := range list {
+		content, err := app.adapter.ToContent(oneResource)
+		if err != nil {
+			return nil, err
+		}
+
+		contentLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(contentLengthBytes, uint64(len(content)))
+		output = append(output, contentLengthBytes...)
+		output = append(output, content...)
+	}
+
+	return output, nil
+}
+
+// ToResources converts bytes to Resources
+func (app *resourcesAdapter) ToResources(content []byte) (Resources, error) {
+	contentLength := uint64(len(content))
+	if contentLength < 8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Resources instance, %d provided", 8, contentLength)
+		return nil, errors.New(str)
+	}
+
+	list := []Resource{}
+	length := binary.LittleEndian.Uint64(content[:8])
+	beginsOn := uint64(8)
+	for i := uint64(0); i < length; i++ {
+		if contentLength < beginsOn+8 {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the size of the Resource (index: %d), %d provided", beginsOn+8, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		dataBeginsOn := beginsOn + 8
+		endsOn := dataBeginsOn + binary.LittleEndian.Uint64(content[beginsOn:dataBeginsOn])
+		if contentLength < endsOn {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the Resource (index: %d), %d provided", endsOn, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		ins, err := app.adapter.ToResource(content[dataBeginsOn:endsOn])
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, ins)
+		beginsOn = endsOn
+	}
+
+	return app.builder.Create().WithList(list).Now()
+}
@@ -0,0 +1,27 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourcesAdapter_Success(t *testing.T) {
+	resources := NewResourcesForTests(5)
+	adapter := NewResourcesAdapter()
+	content, err := adapter.ToContent(resources)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retResources, err := adapter.ToResources(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(resources, retResources) {
+		t.Errorf("the returned resources is invalid")
+		return
+	}
+}
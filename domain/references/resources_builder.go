@@ -0,0 +1,53 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type resourcesBuilder struct {
+	list []Resource
+}
+
+func createResourcesBuilder() ResourcesBuilder {
+	out := resourcesBuilder{
+		list: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *resourcesBuilder) Create() ResourcesBuilder {
+	return createResourcesBuilder()
+}
+
+// WithList adds a list of resources to the builder
+func (app *resourcesBuilder) WithList(list []Resource) ResourcesBuilder {
+	app.list = list
+	return app
+}
+
+// Now builds a new Resources instance
+func (app *resourcesBuilder) Now() (Resources, error) {
+	if app.list != nil && len(app.list) <= 0 {
+		app.list = nil
+	}
+
+	if app.list == nil {
+		return nil, errors.New("there must be at least 1 Resource in order to build a Resources instance")
+	}
+
+	mp := map[string]Resource{}
+	for _, oneResource := range app.list {
+		name := oneResource.Name()
+		if _, ok := mp[name]; ok {
+			str := fmt.Sprintf("the resource (name: %s) is duplicated", name)
+			return nil, errors.New(str)
+		}
+
+		mp[name] = oneResource
+	}
+
+	return createResources(mp, app.list), nil
+}
@@ -1,26 +1,66 @@
 package references
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"time"
 
 	"github.com/steve-care-software/libs/cryptography/hash"
 	"github.com/steve-care-software/libs/cryptography/trees"
 )
 
-const pointerSize = 8 * 2
+const originalPointerSize = 8 * 2
+const pointerSize = originalPointerSize + 1 + 4
 const actionSize = trees.MinHashtreeSize + 1 + 8
 const commitMinSize = 8 + 8 + actionSize
-const contentKeySize = hash.Size + pointerSize + 8 + hash.Size
-const minReferenceSize = contentKeySize + commitMinSize
+const commitSignatureSize = ed25519.PublicKeySize + ed25519.SignatureSize
+const commitParentFlag = 1
+const commitSignatureFlag = 2
+const commitMetadataFlag = 3
+const metadataHashPrefix = "metadata"
+const metadataMaxMessageLength = 4096
+const metadataMaxAnnotations = 64
+const contentKeySize = hash.Size + pointerSize + 8 + hash.Size + 8 + 1 + 8
+const originalContentKeySize = hash.Size + originalPointerSize + 8 + hash.Size
+const minReferenceSize = originalContentKeySize + commitMinSize
+const resourceMinSize = 8 + 1 + 8 + 1
+const tagMinSize = 8 + 1 + hash.Size
+const kindMinSize = 8 + 8 + 1 + 8 + 8
+const kindMaxDescriptionLength = 4096
 
 // NewAdapter creates a new adapter instance
 func NewAdapter() Adapter {
 	contentKeysAdapter := NewContentKeysAdapter()
 	commitsAdapter := NewCommitsAdapter()
+	resourcesAdapter := NewResourcesAdapter()
+	tagsAdapter := NewTagsAdapter()
+	kindsAdapter := NewKindsAdapter()
 	builder := NewBuilder()
 	return createAdapter(
 		contentKeysAdapter,
 		commitsAdapter,
+		resourcesAdapter,
+		tagsAdapter,
+		kindsAdapter,
+		builder,
+	)
+}
+
+// NewOriginalAdapter creates a new adapter instance for the references written before the content keys contained
+// their codec and encryption key, that only contain commits and content keys
+func NewOriginalAdapter() Adapter {
+	contentKeysAdapter := NewOriginalContentKeysAdapter()
+	commitsAdapter := NewCommitsAdapter()
+	resourcesAdapter := NewResourcesAdapter()
+	tagsAdapter := NewTagsAdapter()
+	kindsAdapter := NewKindsAdapter()
+	builder := NewBuilder()
+	return createAdapter(
+		contentKeysAdapter,
+		commitsAdapter,
+		resourcesAdapter,
+		tagsAdapter,
+		kindsAdapter,
 		builder,
 	)
 }
@@ -52,8 +92,9 @@ func NewCommitsBuilder() CommitsBuilder {
 func NewCommitAdapter() CommitAdapter {
 	hashAdapter := hash.NewAdapter()
 	actionAdapter := NewActionAdapter()
+	metadataAdapter := NewMetadataAdapter()
 	builder := NewCommitBuilder()
-	return createCommitAdapter(hashAdapter, actionAdapter, builder)
+	return createCommitAdapter(hashAdapter, actionAdapter, metadataAdapter, builder)
 }
 
 // NewCommitBuilder creates a new commit builder
@@ -64,6 +105,18 @@ func NewCommitBuilder() CommitBuilder {
 	)
 }
 
+// NewMetadataAdapter creates a new metadata adapter
+func NewMetadataAdapter() MetadataAdapter {
+	builder := NewMetadataBuilder()
+	return createMetadataAdapter(builder)
+}
+
+// NewMetadataBuilder creates a new metadata builder
+func NewMetadataBuilder() MetadataBuilder {
+	hashAdapter := hash.NewAdapter()
+	return createMetadataBuilder(hashAdapter)
+}
+
 // NewActionAdapter creates a new action adapter
 func NewActionAdapter() ActionAdapter {
 	hashAdapter := hash.NewAdapter()
@@ -85,6 +138,15 @@ func NewContentKeysAdapter() ContentKeysAdapter {
 	return createContentKeysAdapter(adapter, builder)
 }
 
+// NewOriginalContentKeysAdapter creates a new content keys adapter for the content keys written before they contained their codec and encryption key
+func NewOriginalContentKeysAdapter() ContentKeysAdapter {
+	hashAdapter := hash.NewAdapter()
+	pointerAdapter := createOriginalPointerAdapter(NewPointerBuilder())
+	adapter := createOriginalContentKeyAdapter(hashAdapter, pointerAdapter, NewContentKeyBuilder())
+	builder := NewContentKeysBuilder()
+	return createOriginalContentKeysAdapter(adapter, builder)
+}
+
 // NewContentKeysBuilder creates a new content keys builder
 func NewContentKeysBuilder() ContentKeysBuilder {
 	return createContentKeysBuilder()
@@ -103,6 +165,76 @@ func NewContentKeyBuilder() ContentKeyBuilder {
 	return createContentKeyBuilder()
 }
 
+// NewResourcesAdapter creates a new resources adapter
+func NewResourcesAdapter() ResourcesAdapter {
+	adapter := NewResourceAdapter()
+	builder := NewResourcesBuilder()
+	return createResourcesAdapter(adapter, builder)
+}
+
+// NewResourcesBuilder creates a new resources builder
+func NewResourcesBuilder() ResourcesBuilder {
+	return createResourcesBuilder()
+}
+
+// NewResourceAdapter creates a new resource adapter
+func NewResourceAdapter() ResourceAdapter {
+	builder := NewResourceBuilder()
+	return createResourceAdapter(builder)
+}
+
+// NewResourceBuilder creates a new resource builder
+func NewResourceBuilder() ResourceBuilder {
+	return createResourceBuilder()
+}
+
+// NewTagsAdapter creates a new tags adapter
+func NewTagsAdapter() TagsAdapter {
+	adapter := NewTagAdapter()
+	builder := NewTagsBuilder()
+	return createTagsAdapter(adapter, builder)
+}
+
+// NewTagsBuilder creates a new tags builder
+func NewTagsBuilder() TagsBuilder {
+	return createTagsBuilder()
+}
+
+// NewTagAdapter creates a new tag adapter
+func NewTagAdapter() TagAdapter {
+	hashAdapter := hash.NewAdapter()
+	builder := NewTagBuilder()
+	return createTagAdapter(hashAdapter, builder)
+}
+
+// NewTagBuilder creates a new tag builder
+func NewTagBuilder() TagBuilder {
+	return createTagBuilder()
+}
+
+// NewKindsAdapter creates a new kinds adapter
+func NewKindsAdapter() KindsAdapter {
+	adapter := NewKindAdapter()
+	builder := NewKindsBuilder()
+	return createKindsAdapter(adapter, builder)
+}
+
+// NewKindsBuilder creates a new kinds builder
+func NewKindsBuilder() KindsBuilder {
+	return createKindsBuilder()
+}
+
+// NewKindAdapter creates a new kind adapter
+func NewKindAdapter() KindAdapter {
+	builder := NewKindBuilder()
+	return createKindAdapter(builder)
+}
+
+// NewKindBuilder creates a new kind builder
+func NewKindBuilder() KindBuilder {
+	return createKindBuilder()
+}
+
 // NewPointerAdapter creates a new pointer adapter
 func NewPointerAdapter() PointerAdapter {
 	builder := NewPointerBuilder()
@@ -130,6 +262,9 @@ type Builder interface {
 	Create() Builder
 	WithContentKeys(contentKeys ContentKeys) Builder
 	WithCommits(commits Commits) Builder
+	WithResources(resources Resources) Builder
+	WithTags(tags Tags) Builder
+	WithKinds(kinds Kinds) Builder
 	Now() (Reference, error)
 }
 
@@ -138,6 +273,12 @@ type Reference interface {
 	Commits() Commits
 	HasContentKeys() bool
 	ContentKeys() ContentKeys
+	HasResources() bool
+	Resources() Resources
+	HasTags() bool
+	Tags() Tags
+	HasKinds() bool
+	Kinds() Kinds
 }
 
 // CommitsAdapter represents a commits adapter
@@ -172,6 +313,9 @@ type CommitBuilder interface {
 	WithAction(action Action) CommitBuilder
 	WithParent(parent hash.Hash) CommitBuilder
 	CreatedOn(createdOn time.Time) CommitBuilder
+	WithMetadata(metadata Metadata) CommitBuilder
+	WithSigner(signer crypto.Signer) CommitBuilder
+	WithSignature(author ed25519.PublicKey, signature []byte) CommitBuilder
 	Now() (Commit, error)
 }
 
@@ -182,6 +326,39 @@ type Commit interface {
 	CreatedOn() time.Time
 	HasParent() bool
 	Parent() *hash.Hash
+	HasSignature() bool
+	Signature() Signature
+	HasMetadata() bool
+	Metadata() Metadata
+}
+
+// MetadataAdapter represents a metadata adapter
+type MetadataAdapter interface {
+	ToContent(ins Metadata) ([]byte, error)
+	ToMetadata(content []byte) (Metadata, error)
+}
+
+// MetadataBuilder represents a metadata builder
+type MetadataBuilder interface {
+	Create() MetadataBuilder
+	WithMessage(message string) MetadataBuilder
+	WithAuthor(author string) MetadataBuilder
+	WithAnnotations(annotations map[string]string) MetadataBuilder
+	Now() (Metadata, error)
+}
+
+// Metadata represents the metadata of a commit
+type Metadata interface {
+	Hash() hash.Hash
+	Message() string
+	Author() string
+	Annotations() map[string]string
+}
+
+// Signature represents the ed25519 signature of a commit hash by its author
+type Signature interface {
+	Author() ed25519.PublicKey
+	Data() []byte
 }
 
 // ActionAdapter represents an action adapter
@@ -241,6 +418,8 @@ type ContentKeyBuilder interface {
 	WithKind(kind uint) ContentKeyBuilder
 	WithContent(content Pointer) ContentKeyBuilder
 	WithCommit(commit hash.Hash) ContentKeyBuilder
+	WithCodec(codec uint) ContentKeyBuilder
+	WithKey(key uint) ContentKeyBuilder
 	Now() (ContentKey, error)
 }
 
@@ -250,6 +429,48 @@ type ContentKey interface {
 	Content() Pointer
 	Kind() uint
 	Commit() hash.Hash
+	Codec() uint
+	HasKey() bool
+	Key() *uint
+}
+
+// ResourcesAdapter represents the resources adapter
+type ResourcesAdapter interface {
+	ToContent(ins Resources) ([]byte, error)
+	ToResources(content []byte) (Resources, error)
+}
+
+// ResourcesBuilder represents a resources builder
+type ResourcesBuilder interface {
+	Create() ResourcesBuilder
+	WithList(list []Resource) ResourcesBuilder
+	Now() (Resources, error)
+}
+
+// Resources represents the named resources stored in the reference
+type Resources interface {
+	List() []Resource
+	Fetch(name string) (Resource, error)
+}
+
+// ResourceAdapter represents the resource adapter
+type ResourceAdapter interface {
+	ToContent(ins Resource) ([]byte, error)
+	ToResource(content []byte) (Resource, error)
+}
+
+// ResourceBuilder represents a resource builder
+type ResourceBuilder interface {
+	Create() ResourceBuilder
+	WithName(name string) ResourceBuilder
+	WithData(data []byte) ResourceBuilder
+	Now() (Resource, error)
+}
+
+// Resource represents a named resource stored in the reference, such as a compression dictionary
+type Resource interface {
+	Name() string
+	Data() []byte
 }
 
 // PointerAdapter represents the pointer adapter
@@ -263,6 +484,7 @@ type PointerBuilder interface {
 	Create() PointerBuilder
 	WithLength(length uint) PointerBuilder
 	From(from uint) PointerBuilder
+	WithChecksum(checksum uint32) PointerBuilder
 	Now() (Pointer, error)
 }
 
@@ -270,4 +492,90 @@ type PointerBuilder interface {
 type Pointer interface {
 	From() uint
 	Length() uint
+	HasChecksum() bool
+	Checksum() *uint32
+}
+
+// TagsAdapter represents the tags adapter
+type TagsAdapter interface {
+	ToContent(ins Tags) ([]byte, error)
+	ToTags(content []byte) (Tags, error)
+}
+
+// TagsBuilder represents a tags builder
+type TagsBuilder interface {
+	Create() TagsBuilder
+	WithList(list []Tag) TagsBuilder
+	Now() (Tags, error)
+}
+
+// Tags represents the named tags stored in the reference
+type Tags interface {
+	List() []Tag
+	Fetch(name string) (Tag, error)
+}
+
+// TagAdapter represents the tag adapter
+type TagAdapter interface {
+	ToContent(ins Tag) ([]byte, error)
+	ToTag(content []byte) (Tag, error)
+}
+
+// TagBuilder represents a tag builder
+type TagBuilder interface {
+	Create() TagBuilder
+	WithName(name string) TagBuilder
+	WithCommit(commit hash.Hash) TagBuilder
+	Now() (Tag, error)
+}
+
+// Tag represents an immutable name pinning a commit
+type Tag interface {
+	Name() string
+	Commit() hash.Hash
+}
+
+// KindsAdapter represents the kinds adapter
+type KindsAdapter interface {
+	ToContent(ins Kinds) ([]byte, error)
+	ToKinds(content []byte) (Kinds, error)
+}
+
+// KindsBuilder represents a kinds builder
+type KindsBuilder interface {
+	Create() KindsBuilder
+	WithList(list []Kind) KindsBuilder
+	Now() (Kinds, error)
+}
+
+// Kinds represents the registry of the kinds stored in the reference
+type Kinds interface {
+	List() []Kind
+	Fetch(number uint) (Kind, error)
+	FetchByName(name string) (Kind, error)
+}
+
+// KindAdapter represents the kind adapter
+type KindAdapter interface {
+	ToContent(ins Kind) ([]byte, error)
+	ToKind(content []byte) (Kind, error)
+}
+
+// KindBuilder represents a kind builder
+type KindBuilder interface {
+	Create() KindBuilder
+	WithNumber(number uint) KindBuilder
+	WithName(name string) KindBuilder
+	WithDescription(description string) KindBuilder
+	WithSchema(schema string) KindBuilder
+	Now() (Kind, error)
+}
+
+// Kind represents a registered kind: the name, description and optional schema identifier of a kind number
+type Kind interface {
+	Number() uint
+	Name() string
+	Description() string
+	HasSchema() bool
+	Schema() string
 }
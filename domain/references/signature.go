@@ -0,0 +1,30 @@
+package references
+
+import "crypto/ed25519"
+
+type signature struct {
+	author ed25519.PublicKey
+	data   []byte
+}
+
+func createSignature(
+	author ed25519.PublicKey,
+	data []byte,
+) Signature {
+	out := signature{
+		author: author,
+		data:   data,
+	}
+
+	return &out
+}
+
+// Author returns the public key of the author
+func (obj *signature) Author() ed25519.PublicKey {
+	return obj.author
+}
+
+// Data returns the signature bytes
+func (obj *signature) Data() []byte {
+	return obj.data
+}
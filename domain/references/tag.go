@@ -0,0 +1,30 @@
+package references
+
+import "github.com/steve-care-software/libs/cryptography/hash"
+
+type tag struct {
+	name   string
+	commit hash.Hash
+}
+
+func createTag(
+	name string,
+	commit hash.Hash,
+) Tag {
+	out := tag{
+		name:   name,
+		commit: commit,
+	}
+
+	return &out
+}
+
+// Name returns the name
+func (obj *tag) Name() string {
+	return obj.name
+}
+
+// Commit returns the commit hash
+func (obj *tag) Commit() hash.Hash {
+	return obj.commit
+}
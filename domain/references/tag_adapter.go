@@ -0,0 +1,65 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+type tagAdapter struct {
+	hashAdapter hash.Adapter
+	builder     TagBuilder
+}
+
+func createTagAdapter(
+	hashAdapter hash.Adapter,
+	builder TagBuilder,
+) TagAdapter {
+	out := tagAdapter{
+		hashAdapter: hashAdapter,
+		builder:     builder,
+	}
+
+	return &out
+}
+
+// ToContent converts a Tag instance to bytes
+func (app *tagAdapter) ToContent(ins Tag) ([]byte, error) {
+	name := []byte(ins.Name())
+	nameLengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nameLengthBytes, uint64(len(name)))
+
+	output := []byte{}
+	output = append(output, nameLengthBytes...)
+	output = append(output, name...)
+	output = append(output, ins.Commit().Bytes()...)
+	return output, nil
+}
+
+// ToTag converts bytes to a Tag instance
+func (app *tagAdapter) ToTag(content []byte) (Tag, error) {
+	contentLength := uint64(len(content))
+	if contentLength < tagMinSize {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Tag instance, %d provided", tagMinSize, contentLength)
+		return nil, errors.New(str)
+	}
+
+	nameDelimiter := 8 + binary.LittleEndian.Uint64(content[:8])
+	commitDelimiter := nameDelimiter + hash.Size
+	if contentLength != commitDelimiter {
+		str := fmt.Sprintf("the content was expected to contain %d bytes in order to retrieve the name and commit of the Tag instance, %d provided", commitDelimiter, contentLength)
+		return nil, errors.New(str)
+	}
+
+	pCommit, err := app.hashAdapter.FromBytes(content[nameDelimiter:commitDelimiter])
+	if err != nil {
+		return nil, err
+	}
+
+	return app.builder.Create().
+		WithName(string(content[8:nameDelimiter])).
+		WithCommit(*pCommit).
+		Now()
+}
@@ -0,0 +1,49 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagAdapter_Success(t *testing.T) {
+	tags := []Tag{
+		NewTagForTests("v1.0.0"),
+		NewTagForTests("v1.1.0"),
+	}
+
+	adapter := NewTagAdapter()
+	for _, oneTag := range tags {
+		content, err := adapter.ToContent(oneTag)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		retTag, err := adapter.ToTag(content)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneTag, retTag) {
+			t.Errorf("the returned tag is invalid")
+			return
+		}
+	}
+}
+
+func TestTagAdapter_withInvalidLength_returnsError(t *testing.T) {
+	tag := NewTagForTests("v1.0.0")
+	adapter := NewTagAdapter()
+	content, err := adapter.ToContent(tag)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = adapter.ToTag(content[:len(content)-1])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
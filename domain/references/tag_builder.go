@@ -0,0 +1,51 @@
+package references
+
+import (
+	"errors"
+
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+type tagBuilder struct {
+	name    string
+	pCommit *hash.Hash
+}
+
+func createTagBuilder() TagBuilder {
+	out := tagBuilder{
+		name:    "",
+		pCommit: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *tagBuilder) Create() TagBuilder {
+	return createTagBuilder()
+}
+
+// WithName adds a name to the builder
+func (app *tagBuilder) WithName(name string) TagBuilder {
+	app.name = name
+	return app
+}
+
+// WithCommit adds a commit hash to the builder
+func (app *tagBuilder) WithCommit(commit hash.Hash) TagBuilder {
+	app.pCommit = &commit
+	return app
+}
+
+// Now builds a new Tag instance
+func (app *tagBuilder) Now() (Tag, error) {
+	if app.name == "" {
+		return nil, errors.New("the name is mandatory in order to build a Tag instance")
+	}
+
+	if app.pCommit == nil {
+		return nil, errors.New("the commit is mandatory in order to build a Tag instance")
+	}
+
+	return createTag(app.name, *app.pCommit), nil
+}
@@ -0,0 +1,38 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type tags struct {
+	mp   map[string]Tag
+	list []Tag
+}
+
+func createTags(
+	mp map[string]Tag,
+	list []Tag,
+) Tags {
+	out := tags{
+		mp:   mp,
+		list: list,
+	}
+
+	return &out
+}
+
+// List returns the tags
+func (obj *tags) List() []Tag {
+	return obj.list
+}
+
+// Fetch fetches a tag by name
+func (obj *tags) Fetch(name string) (Tag, error) {
+	if ins, ok := obj.mp[name]; ok {
+		return ins, nil
+	}
+
+	str := fmt.Sprintf("the tag (name: %s) does not exists", name)
+	return nil, errors.New(str)
+}
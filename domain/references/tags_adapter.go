@@ -0,0 +1,83 @@
+package references
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type tagsAdapter struct {
+	adapter TagAdapter
+	builder TagsBuilder
+}
+
+func createTagsAdapter(
+	adapter TagAdapter,
+	builder TagsBuilder,
+) TagsAdapter {
+	out := tagsAdapter{
+		adapter: adapter,
+		builder: builder,
+	}
+
+	return &out
+}
+
+// ToContent converts Tags to bytes
+func (app *tagsAdapter) ToContent(ins Tags) ([]byte, error) {
+	list := ins.List()
+	lengthBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lengthBytes, uint64(len(list)))
+
+	output := []byte{}
+	output = append(output, lengthBytes...)
+	for _, oneTag := range list {
+		content, err := app.adapter.ToContent(oneTag)
+		if err != nil {
+			return nil, err
+		}
+
+		contentLengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(contentLengthBytes, uint64(len(content)))
+		output = append(output, contentLengthBytes...)
+		output = append(output, content...)
+	}
+
+	return output, nil
+}
+
+// ToTags converts bytes to Tags
+func (app *tagsAdapter) ToTags(content []byte) (Tags, error) {
+	contentLength := uint64(len(content))
+	if contentLength < 8 {
+		str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to convert to a Tags instance, %d provided", 8, contentLength)
+		return nil, errors.New(str)
+	}
+
+	list := []Tag{}
+	length := binary.LittleEndian.Uint64(content[:8])
+	beginsOn := uint64(8)
+	for i := uint64(0); i < length; i++ {
+		if contentLength < beginsOn+8 {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the size of the Tag (index: %d), %d provided", beginsOn+8, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		dataBeginsOn := beginsOn + 8
+		endsOn := dataBeginsOn + binary.LittleEndian.Uint64(content[beginsOn:dataBeginsOn])
+		if contentLength < endsOn {
+			str := fmt.Sprintf("the content was expected to contain at least %d bytes in order to retrieve the Tag (index: %d), %d provided", endsOn, i, contentLength)
+			return nil, errors.New(str)
+		}
+
+		ins, err := app.adapter.ToTag(content[dataBeginsOn:endsOn])
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, ins)
+		beginsOn = endsOn
+	}
+
+	return app.builder.Create().WithList(list).Now()
+}
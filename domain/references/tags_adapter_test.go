@@ -0,0 +1,27 @@
+package references
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagsAdapter_Success(t *testing.T) {
+	tags := NewTagsForTests(5)
+	adapter := NewTagsAdapter()
+	content, err := adapter.ToContent(tags)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retTags, err := adapter.ToTags(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(tags, retTags) {
+		t.Errorf("the returned tags is invalid")
+		return
+	}
+}
@@ -0,0 +1,53 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+)
+
+type tagsBuilder struct {
+	list []Tag
+}
+
+func createTagsBuilder() TagsBuilder {
+	out := tagsBuilder{
+		list: nil,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *tagsBuilder) Create() TagsBuilder {
+	return createTagsBuilder()
+}
+
+// WithList adds a list of tags to the builder
+func (app *tagsBuilder) WithList(list []Tag) TagsBuilder {
+	app.list = list
+	return app
+}
+
+// Now builds a new Tags instance
+func (app *tagsBuilder) Now() (Tags, error) {
+	if app.list != nil && len(app.list) <= 0 {
+		app.list = nil
+	}
+
+	if app.list == nil {
+		return nil, errors.New("there must be at least 1 Tag in order to build a Tags instance")
+	}
+
+	mp := map[string]Tag{}
+	for _, oneTag := range app.list {
+		name := oneTag.Name()
+		if _, ok := mp[name]; ok {
+			str := fmt.Sprintf("the tag (name: %s) is duplicated", name)
+			return nil, errors.New(str)
+		}
+
+		mp[name] = oneTag
+	}
+
+	return createTags(mp, app.list), nil
+}
@@ -1,6 +1,7 @@
 package references
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"math/rand"
 	"time"
@@ -42,6 +43,24 @@ func NewReferenceWithContentKeysForTests(maxPeerAmount uint) Reference {
 	return ins
 }
 
+// NewReferenceWithResourcesForTests creates a new reference with resources, and optionally contentKeys, for tests
+func NewReferenceWithResourcesForTests(hasContentKeys bool) Reference {
+	builder := NewBuilder().Create().
+		WithCommits(NewCommitsForTests(32)).
+		WithResources(NewResourcesForTests(3))
+
+	if hasContentKeys {
+		builder.WithContentKeys(NewReferenceWithContentKeysForTests(0).ContentKeys())
+	}
+
+	ins, err := builder.Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
 // NewCommitsForTests creates a new commits for tests
 func NewCommitsForTests(amount uint) Commits {
 	list := []Commit{}
@@ -171,6 +190,41 @@ func NewPointerForTests() Pointer {
 	return pointer
 }
 
+// NewPointerWithChecksumForTests creates a new pointer with checksum for tests
+func NewPointerWithChecksumForTests(checksum uint32) Pointer {
+	pointer := NewPointerForTests()
+	ins, err := NewPointerBuilder().Create().
+		From(pointer.From()).
+		WithLength(pointer.Length()).
+		WithChecksum(checksum).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewContentKeyWithCodecAndKeyForTests creates a new content key with a compression codec and an encryption key for tests
+func NewContentKeyWithCodecAndKeyForTests(codec uint, key uint) ContentKey {
+	contentKey := NewContentKeyForTests()
+	ins, err := NewContentKeyBuilder().Create().
+		WithHash(contentKey.Hash()).
+		WithKind(contentKey.Kind()).
+		WithContent(contentKey.Content()).
+		WithCommit(contentKey.Commit()).
+		WithCodec(codec).
+		WithKey(key).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
 // NewContentKeyForTests creates a new content key for tests
 func NewContentKeyForTests() ContentKey {
 	s1 := rand.NewSource(time.Now().UnixNano())
@@ -206,3 +260,193 @@ func NewContentKeyForTests() ContentKey {
 
 	return ins
 }
+
+// NewResourcesForTests creates new resources for tests
+func NewResourcesForTests(amount uint) Resources {
+	list := []Resource{}
+	for i := 0; i < int(amount); i++ {
+		name := fmt.Sprintf("resource.%d", i)
+		list = append(list, NewResourceForTests(name))
+	}
+
+	ins, err := NewResourcesBuilder().Create().WithList(list).Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewResourceForTests creates a new resource for tests
+func NewResourceForTests(name string) Resource {
+	data := []byte(fmt.Sprintf("this is the data of %s", name))
+	ins, err := NewResourceBuilder().Create().WithName(name).WithData(data).Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewSignerForTests creates a new ed25519 signer from a seed for tests
+func NewSignerForTests(seed string) ed25519.PrivateKey {
+	seedBytes := make([]byte, ed25519.SeedSize)
+	copy(seedBytes, seed)
+	return ed25519.NewKeyFromSeed(seedBytes)
+}
+
+// NewCommitWithParentAndSignerForTests creates a new commit with parent, signed by the signer for tests
+func NewCommitWithParentAndSignerForTests(signer ed25519.PrivateKey) Commit {
+	parent := NewCommitForTests()
+	ins, err := NewCommitBuilder().Create().
+		WithAction(NewActionWithInsert()).
+		WithParent(parent.Hash()).
+		CreatedOn(time.Now().UTC()).
+		WithSigner(signer).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewMetadataForTests creates a new metadata for tests
+func NewMetadataForTests() Metadata {
+	ins, err := NewMetadataBuilder().Create().
+		WithMessage("this is a message").
+		WithAuthor("Jane Doe <jane@example.com>").
+		WithAnnotations(map[string]string{
+			"ticket":  "1234",
+			"release": "v1.2.0",
+		}).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewCommitWithMetadataForTests creates a new commit with parent and metadata for tests
+func NewCommitWithMetadataForTests(metadata Metadata) Commit {
+	parent := NewCommitForTests()
+	ins, err := NewCommitBuilder().Create().
+		WithAction(NewActionWithInsert()).
+		WithParent(parent.Hash()).
+		CreatedOn(time.Now().UTC()).
+		WithMetadata(metadata).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewReferenceWithTagsForTests creates a new reference with tags, and optionally contentKeys and resources, for tests
+func NewReferenceWithTagsForTests(hasContentKeys bool, hasResources bool) Reference {
+	builder := NewBuilder().Create().
+		WithCommits(NewCommitsForTests(32)).
+		WithTags(NewTagsForTests(3))
+
+	if hasContentKeys {
+		builder.WithContentKeys(NewReferenceWithContentKeysForTests(0).ContentKeys())
+	}
+
+	if hasResources {
+		builder.WithResources(NewResourcesForTests(3))
+	}
+
+	ins, err := builder.Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewTagsForTests creates new tags for tests
+func NewTagsForTests(amount uint) Tags {
+	list := []Tag{}
+	for i := 0; i < int(amount); i++ {
+		name := fmt.Sprintf("v1.%d.0", i)
+		list = append(list, NewTagForTests(name))
+	}
+
+	ins, err := NewTagsBuilder().Create().WithList(list).Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewTagForTests creates a new tag for tests
+func NewTagForTests(name string) Tag {
+	ins, err := NewTagBuilder().Create().
+		WithName(name).
+		WithCommit(NewCommitForTests().Hash()).
+		Now()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewReferenceWithKindsForTests creates a new reference with kinds, and optionally tags, for tests
+func NewReferenceWithKindsForTests(hasTags bool) Reference {
+	builder := NewBuilder().Create().
+		WithCommits(NewCommitsForTests(32)).
+		WithKinds(NewKindsForTests(3))
+
+	if hasTags {
+		builder.WithTags(NewTagsForTests(3))
+	}
+
+	ins, err := builder.Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewKindsForTests creates new kinds for tests
+func NewKindsForTests(amount uint) Kinds {
+	list := []Kind{}
+	for i := uint(0); i < amount; i++ {
+		list = append(list, NewKindForTests(i, i%2 == 0))
+	}
+
+	ins, err := NewKindsBuilder().Create().WithList(list).Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
+
+// NewKindForTests creates a new kind, optionally with a schema, for tests
+func NewKindForTests(number uint, hasSchema bool) Kind {
+	builder := NewKindBuilder().Create().
+		WithNumber(number).
+		WithName(fmt.Sprintf("kind_%d", number)).
+		WithDescription(fmt.Sprintf("this is the description of the kind %d", number))
+
+	if hasSchema {
+		builder.WithSchema(fmt.Sprintf("https://example.com/schemas/kind_%d.json", number))
+	}
+
+	ins, err := builder.Now()
+	if err != nil {
+		panic(err)
+	}
+
+	return ins
+}
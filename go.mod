@@ -1,8 +1,9 @@
 module github.com/steve-care-software/databases
 
-go 1.19
+go 1.23
 
 require (
-	github.com/juju/fslock v0.0.0-20160525022230-4d5c94c67b4b // indirect
-	github.com/steve-care-software/libs v0.0.0-20230312132714-485fdb38680d // indirect
+	github.com/juju/fslock v0.0.0-20160525022230-4d5c94c67b4b
+	github.com/klauspost/compress v1.18.0
+	github.com/steve-care-software/libs v0.0.0-20230312132714-485fdb38680d
 )
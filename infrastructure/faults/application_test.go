@@ -0,0 +1,487 @@
+package faults
+
+import (
+	"bytes"
+	"fmt"
+	"syscall"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+const nameForTests = "my_name"
+
+type stepForTests struct {
+	inserts []contents.Content
+	deletes []contents.Content
+}
+
+func TestApplication_crashAtEverySyscall_reopensToValidCommit_Success(t *testing.T) {
+	executeCrashWorkloadForTests(t, func(storage Storage) {})
+}
+
+func TestApplication_crashAtEverySyscall_withTornWrites_reopensToValidCommit_Success(t *testing.T) {
+	executeCrashWorkloadForTests(t, func(storage Storage) {
+		storage.TearWrites()
+	})
+}
+
+func TestApplication_failWithNoSpaceAtEverySyscall_reopensToValidCommit_Success(t *testing.T) {
+	steps, states := newWorkloadForTests()
+	for index := uint(0); ; index++ {
+		storage := NewStorage()
+		storage.FailAt(index, syscall.ENOSPC)
+		app := newApplicationForTests(t, storage)
+		amount, isCreated, err := executeWorkloadForTests(app, steps)
+		storage.Restart()
+		if err == nil {
+			verifyForTests(t, newApplicationForTests(t, storage), states, amount, isCreated)
+			return
+		}
+
+		if !verifyForTests(t, newApplicationForTests(t, storage), states, amount, isCreated) {
+			t.Errorf("the database was invalid after a failure at syscall %d: %s", index, err.Error())
+			return
+		}
+	}
+}
+
+func TestStorage_dropSyncs_losesAcknowledgedCommit(t *testing.T) {
+	storage := NewStorage()
+	storage.DropSyncs()
+	app := newApplicationForTests(t, storage)
+	steps, _ := newWorkloadForTests()
+	_, _, err := executeWorkloadForTests(app, steps[:1])
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	storage.Restart()
+	app = newApplicationForTests(t, storage)
+	pContext, err := app.Open(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	_, err = app.Reference(*pContext)
+	if err == nil {
+		t.Errorf("the acknowledged commit was expected to be lost when the syncs are dropped")
+		return
+	}
+}
+
+func TestStorage_rename_isLostOnRestart_untilDirSynced(t *testing.T) {
+	storage := NewStorage()
+	err := storage.Create("source")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = storage.SyncDir(".")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the rename is lost since its directory is not synced:
+	err = storage.Rename("source", "destination")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	storage.Restart()
+	if !existsForTests(t, storage, "source") || existsForTests(t, storage, "destination") {
+		t.Errorf("the rename was expected to be lost on restart")
+		return
+	}
+
+	// the rename is kept once its directory is synced:
+	err = storage.Rename("source", "destination")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = storage.SyncDir(".")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	storage.Restart()
+	if existsForTests(t, storage, "source") || !existsForTests(t, storage, "destination") {
+		t.Errorf("the rename was expected to be kept on restart")
+		return
+	}
+}
+
+func executeCrashWorkloadForTests(t *testing.T, configureFn func(storage Storage)) {
+	steps, states := newWorkloadForTests()
+	for crashAt := uint(0); ; crashAt++ {
+		storage := NewStorage()
+		configureFn(storage)
+		storage.CrashAt(crashAt)
+
+		app := newApplicationForTests(t, storage)
+		amount, isCreated, err := executeWorkloadForTests(app, steps)
+		hasCrashed := storage.HasCrashed()
+		storage.Restart()
+		if !hasCrashed {
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			verifyForTests(t, newApplicationForTests(t, storage), states, amount, isCreated)
+			return
+		}
+
+		if !verifyForTests(t, newApplicationForTests(t, storage), states, amount, isCreated) {
+			t.Errorf("the database was invalid after a crash at syscall %d (acknowledged: %d, err: %v)", crashAt, amount, err)
+			return
+		}
+	}
+}
+
+func newApplicationForTests(t *testing.T, storage Storage) databases.Application {
+	app, err := files.NewApplicationWithStorage(storage, "", "destination", "backup", 16, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	return app
+}
+
+// newWorkloadForTests returns the workload steps and the expected contents after each amount of commits
+func newWorkloadForTests() ([]stepForTests, [][]contents.Content) {
+	list := []contents.Content{}
+	for i := 0; i < 7; i++ {
+		data := []byte(fmt.Sprintf("this is the data of the content %d", i))
+		list = append(list, contents.NewContentForTests(uint(i%2), data))
+	}
+
+	steps := []stepForTests{
+		{inserts: []contents.Content{list[0], list[1], list[2]}},
+		{inserts: []contents.Content{list[3], list[4]}, deletes: []contents.Content{list[0]}},
+		{inserts: []contents.Content{list[5]}, deletes: []contents.Content{list[1], list[3]}},
+		{deletes: []contents.Content{list[2], list[4], list[5]}},
+		{inserts: []contents.Content{list[6]}},
+	}
+
+	states := [][]contents.Content{{}}
+	for _, oneStep := range steps {
+		previous := states[len(states)-1]
+		current := []contents.Content{}
+		for _, oneContent := range previous {
+			if !containsForTests(oneStep.deletes, oneContent) {
+				current = append(current, oneContent)
+			}
+		}
+
+		states = append(states, append(current, oneStep.inserts...))
+	}
+
+	return steps, states
+}
+
+// executeWorkloadForTests returns the amount of acknowledged commits and if the database creation was acknowledged
+func executeWorkloadForTests(app databases.Application, steps []stepForTests) (int, bool, error) {
+	err := app.New(nameForTests)
+	if err != nil {
+		return 0, false, err
+	}
+
+	pContext, err := app.Open(nameForTests)
+	if err != nil {
+		return 0, true, err
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		return 0, true, err
+	}
+
+	for index, oneStep := range steps {
+		for _, oneContent := range oneStep.inserts {
+			err := app.Insert(*pContext, oneContent)
+			if err != nil {
+				return index, true, err
+			}
+		}
+
+		for _, oneContent := range oneStep.deletes {
+			err := app.Erase(*pContext, oneContent.Kind(), oneContent.Hash())
+			if err != nil {
+				return index, true, err
+			}
+		}
+
+		err = app.Commit(*pContext)
+		if err != nil {
+			return index, true, err
+		}
+	}
+
+	return len(steps), true, app.Close(*pContext)
+}
+
+// verifyForTests verifies that the database reopens to the acknowledged commit, or to the one
+// that was in progress, and that it accepts new commits
+func verifyForTests(t *testing.T, app databases.Application, states [][]contents.Content, amount int, isCreated bool) bool {
+	exists, err := app.Exists(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	if !exists {
+		if isCreated {
+			t.Errorf("the database was expected to exists")
+			return false
+		}
+
+		return true
+	}
+
+	pContext, err := app.Open(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	commitsAmount := 0
+	if reference, err := app.Reference(*pContext); err == nil {
+		commitsAmount = len(reference.Commits().List())
+	}
+
+	if commitsAmount != amount && commitsAmount != amount+1 {
+		t.Errorf("the database was expected to contain %d or %d commits, %d found", amount, amount+1, commitsAmount)
+		return false
+	}
+
+	expected := states[commitsAmount]
+	if commitsAmount > 0 {
+		reference, _ := app.Reference(*pContext)
+		keysAmount := 0
+		if reference.HasContentKeys() {
+			keysAmount = len(reference.ContentKeys().List())
+		}
+
+		if keysAmount != len(expected) {
+			t.Errorf("the database was expected to contain %d contents, %d found", len(expected), keysAmount)
+			return false
+		}
+	}
+
+	for _, oneContent := range expected {
+		retContent, err := app.Retrieve(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return false
+		}
+
+		if !bytes.Equal(oneContent.Data(), retContent.Data()) {
+			t.Errorf("the content (kind: %d, hash: %s) is corrupted", oneContent.Kind(), oneContent.Hash().String())
+			return false
+		}
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	extra := contents.NewContentForTests(3, []byte("this is the data inserted after recovery"))
+	err = app.Insert(*pContext, extra)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func containsForTests(list []contents.Content, content contents.Content) bool {
+	for _, oneContent := range list {
+		if oneContent.Kind() == content.Kind() && oneContent.Hash().Compare(content.Hash()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestApplication_lock_thenCommit_thenLockFromAnotherApplication_returnsError(t *testing.T) {
+	storage := NewStorage()
+	first := newApplicationForTests(t, storage)
+	second := newApplicationForTests(t, storage)
+	err := first.New(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pFirstContext, err := first.Open(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer first.Close(*pFirstContext)
+	err = first.Lock(*pFirstContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = first.Insert(*pFirstContext, contents.NewContentForTests(0, []byte("this is some data")))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = first.Commit(*pFirstContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pSecondContext, err := second.Open(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer second.Close(*pSecondContext)
+	err = second.Lock(*pSecondContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestApplication_rotateKey_crashAtEverySyscall_reopensReadable_thenResumes_Success(t *testing.T) {
+	oldKey := []byte("this is a 32 bytes long key.....")
+	newKey := []byte("this is another 32 bytes key....")
+	keys := map[uint][]byte{
+		0: oldKey,
+		1: newKey,
+	}
+
+	steps, states := newWorkloadForTests()
+	expected := states[len(states)-1]
+	for crashAt := uint(0); ; crashAt++ {
+		storage := NewStorage()
+		_, _, err := executeWorkloadForTests(newKeyedApplicationForTests(t, storage, files.NewKeyProvider(oldKey)), steps)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		storage.Restart()
+		storage.TearWrites()
+		storage.CrashAt(crashAt)
+		err = newKeyedApplicationForTests(t, storage, files.NewKeyRingProvider(1, keys)).RotateKey(nameForTests, 0, 1)
+		hasCrashed := storage.HasCrashed()
+		storage.Restart()
+		if !hasCrashed && err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		// the database remains readable using both keys, then the rotation is resumed:
+		app := newKeyedApplicationForTests(t, storage, files.NewKeyRingProvider(1, keys))
+		if !retrieveAllForTests(t, app, expected) {
+			t.Errorf("the database was invalid after a crash at syscall %d of the rotation (err: %v)", crashAt, err)
+			return
+		}
+
+		err = app.RotateKey(nameForTests, 0, 1)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		// the old key is no longer needed:
+		app = newKeyedApplicationForTests(t, storage, files.NewKeyRingProvider(1, map[uint][]byte{
+			1: newKey,
+		}))
+
+		if !retrieveAllForTests(t, app, expected) {
+			t.Errorf("the database was invalid after resuming the rotation interrupted at syscall %d", crashAt)
+			return
+		}
+
+		if !hasCrashed {
+			return
+		}
+	}
+}
+
+func newKeyedApplicationForTests(t *testing.T, storage Storage, keyProvider files.KeyProvider) files.Application {
+	app, err := files.NewApplicationBuilder().Create().
+		WithStorage(storage).
+		WithDestinationExtension("destination").
+		WithBackupExtension("backup").
+		WithReadChunkSize(16).
+		WithRotationBatchSize(16).
+		WithKeyProvider(keyProvider).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	return app
+}
+
+func retrieveAllForTests(t *testing.T, app databases.Application, expected []contents.Content) bool {
+	pContext, err := app.Open(nameForTests)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	for _, oneContent := range expected {
+		retContent, err := app.Retrieve(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return false
+		}
+
+		if !bytes.Equal(oneContent.Data(), retContent.Data()) {
+			t.Errorf("the content (kind: %d, hash: %s) is corrupted", oneContent.Kind(), oneContent.Hash().String())
+			return false
+		}
+	}
+
+	return true
+}
+
+func existsForTests(t *testing.T, storage Storage, path string) bool {
+	exists, err := storage.Exists(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return exists
+}
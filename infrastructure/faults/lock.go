@@ -0,0 +1,29 @@
+package faults
+
+type lock struct {
+	storage    *storage
+	node       *node
+	path       string
+	generation uint
+}
+
+func createLock(
+	storage *storage,
+	node *node,
+	path string,
+	generation uint,
+) *lock {
+	out := lock{
+		storage:    storage,
+		node:       node,
+		path:       path,
+		generation: generation,
+	}
+
+	return &out
+}
+
+// Unlock releases the lock
+func (obj *lock) Unlock() error {
+	return obj.storage.unlock(obj.node, obj.path, obj.generation)
+}
@@ -0,0 +1,64 @@
+package faults
+
+type write struct {
+	offset int64
+	data   []byte
+}
+
+type node struct {
+	durable  []byte
+	volatile []byte
+	pending  []write
+}
+
+func createNode() *node {
+	out := node{
+		durable:  []byte{},
+		volatile: []byte{},
+		pending:  []write{},
+	}
+
+	return &out
+}
+
+func (obj *node) writeAt(data []byte, offset int64) {
+	obj.volatile = applyWrite(obj.volatile, data, offset)
+	obj.pending = append(obj.pending, write{
+		offset: offset,
+		data:   append([]byte{}, data...),
+	})
+}
+
+func (obj *node) sync() {
+	obj.durable = append([]byte{}, obj.volatile...)
+	obj.pending = []write{}
+}
+
+// crash keeps the durable data, plus the pending writes except the last one when torn,
+// which is only half written
+func (obj *node) crash(isTorn bool) {
+	data := append([]byte{}, obj.durable...)
+	if isTorn && len(obj.pending) > 0 {
+		lastIndex := len(obj.pending) - 1
+		for _, oneWrite := range obj.pending[:lastIndex] {
+			data = applyWrite(data, oneWrite.data, oneWrite.offset)
+		}
+
+		last := obj.pending[lastIndex]
+		data = applyWrite(data, last.data[:len(last.data)/2], last.offset)
+	}
+
+	obj.durable = data
+	obj.volatile = append([]byte{}, data...)
+	obj.pending = []write{}
+}
+
+func applyWrite(current []byte, data []byte, offset int64) []byte {
+	endsOn := int(offset) + len(data)
+	if endsOn > len(current) {
+		current = append(current, make([]byte, endsOn-len(current))...)
+	}
+
+	copy(current[offset:], data)
+	return current
+}
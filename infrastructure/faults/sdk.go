@@ -0,0 +1,32 @@
+package faults
+
+import (
+	"errors"
+
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+// ErrCrashed is returned by every call executed once the storage crashed, until it restarts
+var ErrCrashed = errors.New("the storage crashed")
+
+// NewStorage creates a new fault-injecting storage instance
+func NewStorage() Storage {
+	return createStorage()
+}
+
+// Storage represents an in-memory storage that injects faults, used to test crash recovery.
+//
+// Every call to the storage or to one of its files counts as a syscall.  The written data
+// is only durable once its file is synced, and the creations, removals and renames once
+// their directory is synced.  A crash discards the data and the files that are not durable,
+// or applies part of the data when the writes are torn.
+type Storage interface {
+	files.Storage
+	Syscalls() uint
+	CrashAt(syscall uint)
+	FailAt(syscall uint, err error)
+	TearWrites()
+	DropSyncs()
+	HasCrashed() bool
+	Restart()
+}
@@ -0,0 +1,360 @@
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+type storage struct {
+	mutex      *sync.Mutex
+	nodes      map[string]*node
+	durables   map[string]*node
+	locks      map[*node]uint
+	generation uint
+	syscalls   uint
+	pCrashAt   *uint
+	failures   map[uint]error
+	isTorn     bool
+	isDropSync bool
+	isCrashed  bool
+}
+
+func createStorage() Storage {
+	out := storage{
+		mutex:      &sync.Mutex{},
+		nodes:      map[string]*node{},
+		durables:   map[string]*node{},
+		locks:      map[*node]uint{},
+		generation: 0,
+		syscalls:   0,
+		pCrashAt:   nil,
+		failures:   map[uint]error{},
+		isTorn:     false,
+		isDropSync: false,
+		isCrashed:  false,
+	}
+
+	return &out
+}
+
+// Syscalls returns the amount of syscalls executed since the last restart
+func (app *storage) Syscalls() uint {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	return app.syscalls
+}
+
+// CrashAt makes the storage crash when executing the syscall at the given index
+func (app *storage) CrashAt(syscall uint) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.pCrashAt = &syscall
+}
+
+// FailAt makes the syscall at the given index return the given error, without executing it
+func (app *storage) FailAt(syscall uint, err error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.failures[syscall] = err
+}
+
+// TearWrites makes a crash partially apply the pending writes
+func (app *storage) TearWrites() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.isTorn = true
+}
+
+// DropSyncs makes the syncs succeed without making the data durable
+func (app *storage) DropSyncs() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.isDropSync = true
+}
+
+// HasCrashed returns true if the storage crashed, false otherwise
+func (app *storage) HasCrashed() bool {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	return app.isCrashed
+}
+
+// Restart simulates a reboot: the data and the files that are not durable are lost, the locks are released,
+// the opened files are invalidated and the injected faults are cleared
+func (app *storage) Restart() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.nodes = map[string]*node{}
+	for path, oneNode := range app.durables {
+		app.nodes[path] = oneNode
+	}
+
+	for _, oneNode := range app.nodes {
+		oneNode.crash(app.isTorn)
+	}
+
+	app.locks = map[*node]uint{}
+	app.generation++
+	app.syscalls = 0
+	app.pCrashAt = nil
+	app.failures = map[uint]error{}
+	app.isTorn = false
+	app.isDropSync = false
+	app.isCrashed = false
+}
+
+// Exists returns true if the path is an existing file, false otherwise
+func (app *storage) Exists(path string) (bool, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := app.nodes[path]
+	return ok, nil
+}
+
+// Create creates an empty file
+func (app *storage) Create(path string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return err
+	}
+
+	app.nodes[path] = createNode()
+	return nil
+}
+
+// Open opens an existing file for reading and writing
+func (app *storage) Open(path string) (files.StorageFile, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return nil, err
+	}
+
+	if pNode, ok := app.nodes[path]; ok {
+		return createStorageFile(app, pNode, app.generation), nil
+	}
+
+	str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be opened", path)
+	return nil, errors.New(str)
+}
+
+// Remove removes an existing file
+func (app *storage) Remove(path string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := app.nodes[path]; !ok {
+		str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be removed", path)
+		return errors.New(str)
+	}
+
+	delete(app.nodes, path)
+	return nil
+}
+
+// Rename renames a file, replacing the destination if any
+func (app *storage) Rename(from string, to string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return err
+	}
+
+	if pNode, ok := app.nodes[from]; ok {
+		app.nodes[to] = pNode
+		delete(app.nodes, from)
+		return nil
+	}
+
+	str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be renamed", from)
+	return errors.New(str)
+}
+
+// SyncDir makes the creations, removals and renames of the files of the directory durable
+func (app *storage) SyncDir(path string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return err
+	}
+
+	dirPath := filepath.Clean(path)
+	for onePath := range app.durables {
+		if _, ok := app.nodes[onePath]; !ok && filepath.Dir(onePath) == dirPath {
+			delete(app.durables, onePath)
+		}
+	}
+
+	for onePath, oneNode := range app.nodes {
+		if filepath.Dir(onePath) == dirPath {
+			app.durables[onePath] = oneNode
+		}
+	}
+
+	return nil
+}
+
+// Lock acquires the lock on the file of a path, creating it when missing, or returns an error if it is already locked.
+// The lock follows the file: replacing the path by another file does not carry the lock
+func (app *storage) Lock(path string) (files.StorageLock, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.syscall()
+	if err != nil {
+		return nil, err
+	}
+
+	pNode, ok := app.nodes[path]
+	if !ok {
+		pNode = createNode()
+		app.nodes[path] = pNode
+	}
+
+	if _, ok := app.locks[pNode]; ok {
+		str := fmt.Sprintf("the path (%s) is already locked", path)
+		return nil, errors.New(str)
+	}
+
+	app.locks[pNode] = app.generation
+	return createLock(app, pNode, path, app.generation), nil
+}
+
+func (app *storage) unlock(pNode *node, path string, generation uint) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.fileSyscall(generation)
+	if err != nil {
+		return err
+	}
+
+	if current, ok := app.locks[pNode]; ok && current == generation {
+		delete(app.locks, pNode)
+		return nil
+	}
+
+	str := fmt.Sprintf("the path (%s) is not locked and therefore cannot be unlocked", path)
+	return errors.New(str)
+}
+
+func (app *storage) readAt(pNode *node, generation uint, data []byte, offset int64) (int, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.fileSyscall(generation)
+	if err != nil {
+		return 0, err
+	}
+
+	if offset >= int64(len(pNode.volatile)) {
+		return 0, io.EOF
+	}
+
+	amount := copy(data, pNode.volatile[offset:])
+	if amount < len(data) {
+		return amount, io.EOF
+	}
+
+	return amount, nil
+}
+
+func (app *storage) writeAt(pNode *node, generation uint, data []byte, offset int64) (int, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.fileSyscall(generation)
+	if err != nil {
+		return 0, err
+	}
+
+	pNode.writeAt(data, offset)
+	return len(data), nil
+}
+
+func (app *storage) size(pNode *node, generation uint) (int64, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.fileSyscall(generation)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(pNode.volatile)), nil
+}
+
+func (app *storage) sync(pNode *node, generation uint) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	err := app.fileSyscall(generation)
+	if err != nil {
+		return err
+	}
+
+	if !app.isDropSync {
+		pNode.sync()
+	}
+
+	return nil
+}
+
+func (app *storage) close(generation uint) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	return app.fileSyscall(generation)
+}
+
+func (app *storage) fileSyscall(generation uint) error {
+	if generation != app.generation {
+		return ErrCrashed
+	}
+
+	return app.syscall()
+}
+
+func (app *storage) syscall() error {
+	if app.isCrashed {
+		return ErrCrashed
+	}
+
+	index := app.syscalls
+	app.syscalls++
+	if app.pCrashAt != nil && *app.pCrashAt == index {
+		app.isCrashed = true
+		return ErrCrashed
+	}
+
+	if err, ok := app.failures[index]; ok {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package faults
+
+import (
+	"errors"
+
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+type storageFile struct {
+	storage    *storage
+	pNode      *node
+	generation uint
+	isClosed   bool
+}
+
+func createStorageFile(
+	storage *storage,
+	pNode *node,
+	generation uint,
+) files.StorageFile {
+	out := storageFile{
+		storage:    storage,
+		pNode:      pNode,
+		generation: generation,
+		isClosed:   false,
+	}
+
+	return &out
+}
+
+// ReadAt reads data at offset
+func (obj *storageFile) ReadAt(data []byte, offset int64) (int, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore cannot be read")
+	}
+
+	return obj.storage.readAt(obj.pNode, obj.generation, data, offset)
+}
+
+// WriteAt writes data at offset, the data is durable once synced
+func (obj *storageFile) WriteAt(data []byte, offset int64) (int, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore cannot be written")
+	}
+
+	return obj.storage.writeAt(obj.pNode, obj.generation, data, offset)
+}
+
+// Size returns the size of the file
+func (obj *storageFile) Size() (int64, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore its size cannot be returned")
+	}
+
+	return obj.storage.size(obj.pNode, obj.generation)
+}
+
+// Sync makes the written data durable
+func (obj *storageFile) Sync() error {
+	if obj.isClosed {
+		return errors.New("the file is closed and therefore cannot be synced")
+	}
+
+	return obj.storage.sync(obj.pNode, obj.generation)
+}
+
+// Close closes the file
+func (obj *storageFile) Close() error {
+	if obj.isClosed {
+		return errors.New("the file is already closed")
+	}
+
+	obj.isClosed = true
+	return obj.storage.close(obj.generation)
+}
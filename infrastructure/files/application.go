@@ -1,25 +1,41 @@
 package files
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
-	"io"
-	"os"
 	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
-	"github.com/juju/fslock"
 	databases "github.com/steve-care-software/databases/applications"
 	"github.com/steve-care-software/databases/domain/contents"
 	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
 	"github.com/steve-care-software/libs/cryptography/trees"
 )
 
 type application struct {
+	storage                     Storage
 	onOpenFn                    databases.OnOpenFn
+	onBeforeCommitFn            databases.OnBeforeCommitFn
+	onAfterCommitFn             databases.OnAfterCommitFn
+	instrumentation             databases.Instrumentation
+	keyProvider                 KeyProvider
+	compressions                map[uint]uint
+	codecs                      map[uint]codec
+	signer                      crypto.Signer
+	trustedAuthors              map[string]bool
+	isSignatureRequired         bool
+	indexes                     map[uint]map[string]IndexFn
+	readCache                   *readCache
 	contentsBuilder             contents.Builder
 	contentBuilder              contents.ContentBuilder
 	referenceAdapter            references.Adapter
 	referenceBuilder            references.Builder
+	referenceContentKeysAdapter references.ContentKeysAdapter
+	referenceOriginalAdapter    references.Adapter
 	referenceContentKeysBuilder references.ContentKeysBuilder
 	referenceContentKeyBuilder  references.ContentKeyBuilder
 	referenceCommitsBuilder     references.CommitsBuilder
@@ -27,20 +43,46 @@ type application struct {
 	referenceCommitBuilder      references.CommitBuilder
 	referenceActionBuilder      references.ActionBuilder
 	referencePointerBuilder     references.PointerBuilder
+	referenceResourcesBuilder   references.ResourcesBuilder
+	referenceResourceBuilder    references.ResourceBuilder
+	referenceTagsBuilder        references.TagsBuilder
+	referenceTagBuilder         references.TagBuilder
+	referenceKindsBuilder       references.KindsBuilder
 	hashTreeBuilder             trees.Builder
 	dirPath                     string
 	dstExtension                string
 	bckExtension                string
 	readChunkSize               uint
+	rotationBatchSize           uint
+	nextIdentifier              uint
 	contexts                    map[uint]*context
+	nextSubscription            uint
+	subscriptions               map[uint]*subscription
+	subscriptionsMutex          sync.Mutex
+	changes                     map[string]uint
+	changesMutex                sync.Mutex
 }
 
 func createApplication(
+	storage Storage,
 	onOpenFn databases.OnOpenFn,
+	onBeforeCommitFn databases.OnBeforeCommitFn,
+	onAfterCommitFn databases.OnAfterCommitFn,
+	instrumentation databases.Instrumentation,
+	keyProvider KeyProvider,
+	compressions map[uint]uint,
+	codecs map[uint]codec,
+	signer crypto.Signer,
+	trustedAuthors map[string]bool,
+	isSignatureRequired bool,
+	indexes map[uint]map[string]IndexFn,
+	readCache *readCache,
 	contentsBuilder contents.Builder,
 	contentBuilder contents.ContentBuilder,
 	referenceAdapter references.Adapter,
 	referenceBuilder references.Builder,
+	referenceContentKeysAdapter references.ContentKeysAdapter,
+	referenceOriginalAdapter references.Adapter,
 	referenceContentKeysBuilder references.ContentKeysBuilder,
 	referenceContentKeyBuilder references.ContentKeyBuilder,
 	referenceCommitsBuilder references.CommitsBuilder,
@@ -48,18 +90,38 @@ func createApplication(
 	referenceCommitBuilder references.CommitBuilder,
 	referenceActionBuilder references.ActionBuilder,
 	referencePointerBuilder references.PointerBuilder,
+	referenceResourcesBuilder references.ResourcesBuilder,
+	referenceResourceBuilder references.ResourceBuilder,
+	referenceTagsBuilder references.TagsBuilder,
+	referenceTagBuilder references.TagBuilder,
+	referenceKindsBuilder references.KindsBuilder,
 	hashTreeBuilder trees.Builder,
 	dirPath string,
 	dstExtension string,
 	bckExtension string,
 	readChunkSize uint,
-) databases.Application {
+	rotationBatchSize uint,
+) Application {
 	out := application{
+		storage:                     storage,
 		onOpenFn:                    onOpenFn,
+		onBeforeCommitFn:            onBeforeCommitFn,
+		onAfterCommitFn:             onAfterCommitFn,
+		instrumentation:             instrumentation,
+		keyProvider:                 keyProvider,
+		compressions:                compressions,
+		codecs:                      codecs,
+		signer:                      signer,
+		trustedAuthors:              trustedAuthors,
+		isSignatureRequired:         isSignatureRequired,
+		indexes:                     indexes,
+		readCache:                   readCache,
 		contentsBuilder:             contentsBuilder,
 		contentBuilder:              contentBuilder,
 		referenceAdapter:            referenceAdapter,
 		referenceBuilder:            referenceBuilder,
+		referenceContentKeysAdapter: referenceContentKeysAdapter,
+		referenceOriginalAdapter:    referenceOriginalAdapter,
 		referenceContentKeysBuilder: referenceContentKeysBuilder,
 		referenceContentKeyBuilder:  referenceContentKeyBuilder,
 		referenceCommitsBuilder:     referenceCommitsBuilder,
@@ -67,12 +129,22 @@ func createApplication(
 		referenceCommitBuilder:      referenceCommitBuilder,
 		referenceActionBuilder:      referenceActionBuilder,
 		referencePointerBuilder:     referencePointerBuilder,
+		referenceResourcesBuilder:   referenceResourcesBuilder,
+		referenceResourceBuilder:    referenceResourceBuilder,
+		referenceTagsBuilder:        referenceTagsBuilder,
+		referenceTagBuilder:         referenceTagBuilder,
+		referenceKindsBuilder:       referenceKindsBuilder,
 		hashTreeBuilder:             hashTreeBuilder,
 		dirPath:                     dirPath,
 		dstExtension:                dstExtension,
 		bckExtension:                bckExtension,
 		readChunkSize:               readChunkSize,
+		rotationBatchSize:           rotationBatchSize,
+		nextIdentifier:              0,
 		contexts:                    map[uint]*context{},
+		nextSubscription:            0,
+		subscriptions:               map[uint]*subscription{},
+		changes:                     map[string]uint{},
 	}
 
 	return &out
@@ -80,57 +152,81 @@ func createApplication(
 
 // Exists returns true if the database exists, false otherwise
 func (app *application) Exists(name string) (bool, error) {
-	path := filepath.Join(app.dirPath, name)
-	fileInfo, err := os.Stat(path)
-	if err == nil {
-		return !fileInfo.IsDir(), nil
+	err := app.recover(name)
+	if err != nil {
+		return false, err
 	}
 
-	return false, nil
+	path := filepath.Join(app.dirPath, name)
+	return app.storage.Exists(path)
 }
 
 // New creates a new database
 func (app *application) New(name string) error {
-	if _, err := os.Stat(app.dirPath); errors.Is(err, os.ErrNotExist) {
-		err := os.MkdirAll(app.dirPath, filePermission)
-		if err != nil {
-			return err
-		}
+	exists, err := app.Exists(name)
+	if err != nil {
+		return err
 	}
 
-	path := filepath.Join(app.dirPath, name)
-	_, err := os.Stat(path)
-	if err == nil {
+	if exists {
 		str := fmt.Sprintf("the database (name: %s) already exists and therefore cannot be created again", name)
 		return errors.New(str)
 	}
 
-	file, err := os.Create(path)
+	path := filepath.Join(app.dirPath, name)
+	err = app.storage.Create(path)
 	if err != nil {
 		return err
 	}
 
-	return file.Close()
+	return app.storage.SyncDir(filepath.Dir(path))
 }
 
 // Delete deletes an existing database
 func (app *application) Delete(name string) error {
-	path := filepath.Join(app.dirPath, name)
-	pInfo, err := os.Stat(path)
+	exists, err := app.Exists(name)
 	if err != nil {
 		return err
 	}
 
-	if pInfo.IsDir() {
-		str := fmt.Sprintf("the name (%s) was expected to be a file, not a directory", name)
+	if !exists {
+		str := fmt.Sprintf("the database (name: %s) does not exists and therefore cannot be deleted", name)
 		return errors.New(str)
 	}
 
-	return os.Remove(path)
+	path := filepath.Join(app.dirPath, name)
+	err = app.storage.Remove(path)
+	if err != nil {
+		return err
+	}
+
+	return app.storage.SyncDir(filepath.Dir(path))
 }
 
 // Open opens a context on a given database
 func (app *application) Open(name string) (*uint, error) {
+	return app.open(name, nil, false)
+}
+
+// OpenTag opens a read-only context on a given database, as it was at the commit pinned by the tag
+func (app *application) OpenTag(name string, tag string) (*uint, error) {
+	return app.open(name, &tag, false)
+}
+
+func (app *application) open(name string, pTag *string, isMapped bool) (*uint, error) {
+	span := app.startSpan(databases.OperationOpen, name)
+	beginsOn := time.Now()
+	pIdentifier, err := app.openContext(name, pTag, isMapped)
+	amount := uint(0)
+	if pIdentifier != nil {
+		amount = app.contexts[*pIdentifier].dataOffset
+	}
+
+	app.recordOperation(span, databases.OperationOpen, beginsOn, amount, err)
+	return pIdentifier, err
+}
+
+func (app *application) openContext(name string, pTag *string, isMapped bool) (*uint, error) {
 	for _, oneContext := range app.contexts {
 		if oneContext.name == name {
 			str := fmt.Sprintf("there is already an open context for the provided name: %s", name)
@@ -138,40 +234,82 @@ func (app *application) Open(name string) (*uint, error) {
 		}
 	}
 
+	// recover from an interrupted commit, if any:
+	err := app.recover(name)
+	if err != nil {
+		return nil, err
+	}
+
 	// open the connection:
 	path := filepath.Join(app.dirPath, name)
-	pConn, err := os.Open(path)
+	conn, err := app.storage.Open(path)
 	if err != nil {
 		return nil, err
 	}
 
-	// create a Lock instance on the path:
-	pLock := fslock.New(path)
-
 	// create the context:
 	pContext := &context{
-		identifier: uint(len(app.contexts)),
-		pConn:      pConn,
-		pLock:      pLock,
+		identifier: app.nextIdentifier,
+		conn:       conn,
 		name:       name,
 		insertList: []contents.Content{},
 		delList:    map[string]references.ContentKey{},
+		pTag:       pTag,
+		isMapped:   isMapped,
+		changes:    app.fetchChanges(name),
 	}
 
-	// execute the open callback
-	err = app.onOpenFn(pContext.identifier)
+	// read the reference, if any:
+	err = app.readReference(pContext)
 	if err != nil {
+		app.unmap(pContext)
+		conn.Close()
 		return nil, err
 	}
 
-	//app.contexts[pContext.identifier] = pContext
+	// execute the open callback
+	if app.onOpenFn != nil {
+		err = app.onOpenFn(pContext.identifier)
+		if err != nil {
+			app.unmap(pContext)
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	app.contexts[pContext.identifier] = pContext
+	app.nextIdentifier++
 	return &pContext.identifier, nil
 }
 
 // Lock locks the database file using the provided context
 func (app *application) Lock(context uint) error {
+	span := app.startSpan(databases.OperationLock, app.contextName(context))
+	beginsOn := time.Now()
+	err := app.lock(context)
+	app.recordOperation(span, databases.OperationLock, beginsOn, 0, err)
+	return err
+}
+
+func (app *application) lock(context uint) error {
 	if pContext, ok := app.contexts[context]; ok {
-		return pContext.pLock.TryLock()
+		if pContext.lock != nil {
+			str := fmt.Sprintf("the given context (%d) is already locked", context)
+			return errors.New(str)
+		}
+
+		beginsOn := time.Now()
+		lock, err := app.storage.Lock(app.lockPath(pContext.name))
+		if app.instrumentation != nil {
+			app.instrumentation.RecordLockWait(time.Since(beginsOn), err)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		pContext.lock = lock
+		return nil
 	}
 
 	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Lock using this context", context)
@@ -181,7 +319,18 @@ func (app *application) Lock(context uint) error {
 // Unlock unlocks the database file using the provided context
 func (app *application) Unlock(context uint) error {
 	if pContext, ok := app.contexts[context]; ok {
-		return pContext.pLock.Unlock()
+		if pContext.lock == nil {
+			str := fmt.Sprintf("the given context (%d) is not locked and therefore cannot be unlocked", context)
+			return errors.New(str)
+		}
+
+		err := pContext.lock.Unlock()
+		if err != nil {
+			return err
+		}
+
+		pContext.lock = nil
+		return nil
 	}
 
 	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Unlock using this context", context)
@@ -190,9 +339,21 @@ func (app *application) Unlock(context uint) error {
 
 // Read reads data using context, at offset, for a given length
 func (app *application) Read(context uint, offset uint, length uint) ([]byte, error) {
+	span := app.startSpan(databases.OperationRead, app.contextName(context))
+	beginsOn := time.Now()
+	data, err := app.read(context, offset, length)
+	app.recordOperation(span, databases.OperationRead, beginsOn, uint(len(data)), err)
+	return data, err
+}
+
+func (app *application) read(context uint, offset uint, length uint) ([]byte, error) {
 	if pContext, ok := app.contexts[context]; ok {
+		if pContext.mapping != nil {
+			return readMapping(pContext, offset, length)
+		}
+
 		contentBytes := make([]byte, length)
-		refContentAmount, err := pContext.pConn.ReadAt(contentBytes, int64(offset))
+		refContentAmount, err := pContext.conn.ReadAt(contentBytes, int64(offset))
 		if err != nil {
 			return nil, err
 		}
@@ -211,20 +372,27 @@ func (app *application) Read(context uint, offset uint, length uint) ([]byte, er
 
 // Write writes data using context, at offset
 func (app *application) Write(context uint, offset int64, data []byte) error {
+	span := app.startSpan(databases.OperationWrite, app.contextName(context))
+	beginsOn := time.Now()
+	err := app.write(context, offset, data)
+	amount := uint(0)
+	if err == nil {
+		amount = uint(len(data))
+	}
+
+	app.recordOperation(span, databases.OperationWrite, beginsOn, amount, err)
+	return err
+}
+
+func (app *application) write(context uint, offset int64, data []byte) error {
 	if pContext, ok := app.contexts[context]; ok {
-		// seek the file at the from byte:
-		seekOffset, err := pContext.pConn.Seek(offset, 0)
+		err := app.verifyWritable(pContext)
 		if err != nil {
 			return err
 		}
 
-		if seekOffset != offset {
-			str := fmt.Sprintf("the offset was expected to be %d, %d returned after file seek", offset, seekOffset)
-			return errors.New(str)
-		}
-
-		// write the data on disk:
-		amountWritten, err := pContext.pConn.Write(data)
+		// write the data on the storage, at offset:
+		amountWritten, err := pContext.conn.WriteAt(data, offset)
 		if err != nil {
 			return err
 		}
@@ -242,73 +410,316 @@ func (app *application) Write(context uint, offset int64, data []byte) error {
 	return errors.New(str)
 }
 
-// Copy copies databases by source and destination names
+// Copy replaces the database of the context by the destination database
 func (app *application) Copy(context uint, destination string) error {
 	if pContext, ok := app.contexts[context]; ok {
-		// create the source path:
-		sourcePath := filepath.Join(app.dirPath, pContext.name)
+		err := app.verifyWritable(pContext)
+		if err != nil {
+			return err
+		}
 
-		// create the back file:
-		backupName := fmt.Sprintf("%s%s%s", pContext.name, fileNameExtensionDelimiter, app.bckExtension)
-		backupPath := filepath.Join(app.dirPath, backupName)
+		// create the paths:
+		sourcePath := filepath.Join(app.dirPath, pContext.name)
+		backupPath := app.backupPath(pContext.name)
+		destinationPath := filepath.Join(app.dirPath, destination)
 
-		// copy the source database to a backup file:
-		backupPtr, err := os.Create(backupPath)
+		// make sure the destination exists before touching the source:
+		exists, err := app.storage.Exists(destinationPath)
 		if err != nil {
 			return err
 		}
 
-		_, err = io.Copy(backupPtr, pContext.pConn)
+		if !exists {
+			str := fmt.Sprintf("the destination database (name: %s) does not exists and therefore cannot be copied", destination)
+			return errors.New(str)
+		}
+
+		// close the source connection:
+		err = pContext.conn.Close()
 		if err != nil {
 			return err
 		}
 
-		// close the backup file:
-		err = backupPtr.Close()
+		// swap the databases, then re-open the connection on the source database:
+		swapErr := app.swap(sourcePath, backupPath, destinationPath)
+		conn, err := app.storage.Open(sourcePath)
 		if err != nil {
 			return err
 		}
 
-		// close the source connection:
-		err = pContext.pConn.Close()
+		pContext.conn = conn
+		if swapErr != nil {
+			return swapErr
+		}
+
+		return app.readReference(pContext)
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Copy using this context", context)
+	return errors.New(str)
+}
+
+// Reference returns the reference of the database using context
+func (app *application) Reference(context uint) (references.Reference, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if pContext.reference == nil {
+			str := fmt.Sprintf("the database (name: %s) does not contain any commit yet and therefore has no Reference", pContext.name)
+			return nil, errors.New(str)
+		}
+
+		return pContext.reference, nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot retrieve the Reference using this context", context)
+	return nil, errors.New(str)
+}
+
+// Retrieve retrieves a committed content by kind and hash using context
+func (app *application) Retrieve(context uint, kind uint, hash hash.Hash) (contents.Content, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		contentKey, err := app.fetchContentKey(pContext, kind, hash)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		data, err := app.retrieveData(pContext, contentKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return app.contentBuilder.Create().
+			WithHash(contentKey.Hash()).
+			WithKind(contentKey.Kind()).
+			WithData(data).
+			Now()
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Retrieve using this context", context)
+	return nil, errors.New(str)
+}
+
+// retrieveData returns the data of the content key, from the read cache when it contains it
+func (app *application) retrieveData(pContext *context, contentKey references.ContentKey) ([]byte, error) {
+	cacheKey := ""
+	if app.readCache != nil {
+		cacheKey = createReadCacheKey(pContext.name, contentKey)
+		if data, ok := app.readCache.get(cacheKey); ok {
+			return data, nil
+		}
+	}
+
+	pointer := contentKey.Content()
+	offset := pContext.dataOffset + pointer.From()
+	encrypted, err := app.read(pContext.identifier, offset, pointer.Length())
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifyContent(contentKey, offset, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := app.decryptContent(contentKey, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := app.decompress(pContext, contentKey, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.readCache != nil {
+		// the data of a mapped context refers to its mapping, which is unmapped once the context is closed:
+		cached := data
+		if pContext.isMapped {
+			cached = append([]byte{}, data...)
 		}
 
-		// delete the source database:
-		err = os.Remove(sourcePath)
+		app.readCache.put(cacheKey, cached)
+	}
+
+	return data, nil
+}
+
+// Insert adds a content to the pending insert list of the context
+func (app *application) Insert(context uint, content contents.Content) error {
+	if pContext, ok := app.contexts[context]; ok {
+		err := app.verifyWritable(pContext)
 		if err != nil {
 			return err
 		}
 
-		// rename the destination database to source:
-		destinationFile := fmt.Sprintf("%s%s%s", pContext.name, fileNameExtensionDelimiter, app.dstExtension)
-		destinationPath := filepath.Join(app.dirPath, destinationFile)
-		err = os.Rename(destinationPath, sourcePath)
+		kind := content.Kind()
+		hash := content.Hash()
+		if _, err := app.fetchContentKey(pContext, kind, hash); err == nil {
+			str := fmt.Sprintf("the content (kind: %d, hash: %s) already exists and therefore cannot be inserted again", kind, hash.String())
+			return errors.New(str)
+		}
+
+		for _, oneContent := range pContext.insertList {
+			if oneContent.Kind() == kind && oneContent.Hash().Compare(hash) {
+				str := fmt.Sprintf("the content (kind: %d, hash: %s) is already pending an insert", kind, hash.String())
+				return errors.New(str)
+			}
+		}
+
+		pContext.insertList = append(pContext.insertList, content)
+		return nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Insert using this context", context)
+	return errors.New(str)
+}
+
+// Erase adds a committed content to the pending delete list of the context
+func (app *application) Erase(context uint, kind uint, hash hash.Hash) error {
+	if pContext, ok := app.contexts[context]; ok {
+		err := app.verifyWritable(pContext)
 		if err != nil {
 			return err
 		}
 
-		// delete the backup file:
-		err = os.Remove(backupPath)
+		contentKey, err := app.fetchContentKey(pContext, kind, hash)
 		if err != nil {
 			return err
 		}
 
+		keyname := createKeyname(kind, hash)
+		if _, ok := pContext.delList[keyname]; ok {
+			str := fmt.Sprintf("the content (kind: %d, hash: %s) is already pending a delete", kind, hash.String())
+			return errors.New(str)
+		}
+
+		pContext.delList[keyname] = contentKey
+		return nil
 	}
 
-	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Copy using this context", context)
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Erase using this context", context)
 	return errors.New(str)
 }
 
+// Commit writes the pending inserts and deletes of the context to the database
+func (app *application) Commit(context uint) error {
+	return app.commit(context, nil)
+}
+
+// CommitWithMetadata writes the pending inserts and deletes of the context to the database, in a commit containing the metadata
+func (app *application) CommitWithMetadata(context uint, metadata references.Metadata) error {
+	return app.commit(context, metadata)
+}
+
+func (app *application) commit(context uint, metadata references.Metadata) error {
+	span := app.startSpan(databases.OperationCommit, app.contextName(context))
+	beginsOn := time.Now()
+	size, err := app.commitContext(context, metadata)
+	app.recordOperation(span, databases.OperationCommit, beginsOn, size, err)
+	return err
+}
+
+// commitContext commits the pending inserts and deletes of the context, then returns the amount of bytes
+// appended to the data region
+func (app *application) commitContext(context uint, metadata references.Metadata) (uint, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		if len(pContext.insertList) <= 0 && len(pContext.delList) <= 0 {
+			str := fmt.Sprintf("the given context (%d) does not contain any pending insert or delete and therefore cannot Commit", context)
+			return 0, errors.New(str)
+		}
+
+		err := app.verifyWritable(pContext)
+		if err != nil {
+			return 0, err
+		}
+
+		err = app.verifyLocked(pContext)
+		if err != nil {
+			return 0, err
+		}
+
+		// execute the before commit callback, that can veto the commit:
+		if app.onBeforeCommitFn != nil {
+			err = app.onBeforeCommitFn(context, app.pendingInserts(pContext), app.pendingDeletes(pContext))
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		// build the commit, then make sure it would be accepted once written:
+		commit, err := app.buildCommit(pContext, metadata)
+		if err != nil {
+			return 0, err
+		}
+
+		err = app.verifyCommit(commit)
+		if err != nil {
+			return 0, err
+		}
+
+		// build the updated reference and the data to append:
+		reference, data, err := app.buildReference(pContext, commit)
+		if err != nil {
+			return 0, err
+		}
+
+		referenceBytes, err := app.referenceAdapter.ToContent(reference)
+		if err != nil {
+			return 0, err
+		}
+
+		referenceBytes, err = app.encryptReference(referenceBytes)
+		if err != nil {
+			return 0, err
+		}
+
+		err = app.rewrite(pContext, referenceBytes, data)
+		if err != nil {
+			return 0, err
+		}
+
+		if app.instrumentation != nil {
+			app.instrumentation.RecordCommit(uint(len(data)), uint(len(pContext.insertList)), uint(len(pContext.delList)))
+		}
+
+		app.publishCommit(pContext, reference, commit)
+		pContext.insertList = []contents.Content{}
+		pContext.delList = map[string]references.ContentKey{}
+
+		// execute the after commit callback:
+		if app.onAfterCommitFn != nil {
+			app.onAfterCommitFn(context, commit)
+		}
+
+		return uint(len(data)), nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Commit using this context", context)
+	return 0, errors.New(str)
+}
+
 // Close closes a context
 func (app *application) Close(context uint) error {
 	if pContext, ok := app.contexts[context]; ok {
-		err := pContext.pConn.Close()
+		if pContext.lock != nil {
+			err := app.Unlock(context)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := app.unmap(pContext)
+		if err != nil {
+			return err
+		}
+
+		err = pContext.conn.Close()
 		if err != nil {
 			return err
 		}
 
+		app.closeDictionaries(pContext)
 		delete(app.contexts, context)
 		return nil
 	}
@@ -316,3 +727,606 @@ func (app *application) Close(context uint) error {
 	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot be closed", context)
 	return errors.New(str)
 }
+
+// startSpan starts the span of the operation using the instrumentation, if any
+func (app *application) startSpan(operation string, name string) databases.Span {
+	if app.instrumentation == nil {
+		return nil
+	}
+
+	return app.instrumentation.StartSpan(operation, name)
+}
+
+// recordOperation records the operation using the instrumentation, if any, then ends its span
+func (app *application) recordOperation(span databases.Span, operation string, beginsOn time.Time, amount uint, err error) {
+	if app.instrumentation == nil {
+		return
+	}
+
+	app.instrumentation.RecordOperation(operation, time.Since(beginsOn), amount, err)
+	span.End(err)
+}
+
+// contextName returns the name of the database of the context, or an empty string when the context does not exists
+func (app *application) contextName(context uint) string {
+	if pContext, ok := app.contexts[context]; ok {
+		return pContext.name
+	}
+
+	return ""
+}
+
+func (app *application) readReference(pContext *context) error {
+	if pContext.isMapped {
+		err := app.remap(pContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	size, err := pContext.conn.Size()
+	if err != nil {
+		return err
+	}
+
+	if size <= 0 {
+		pContext.pHeader = nil
+		pContext.reference = nil
+		pContext.pReferenceKey = nil
+		pContext.dataOffset = 0
+		return nil
+	}
+
+	pHeader, reference, pReferenceKey, err := app.fetchReference(pContext.conn, pContext.name, size)
+	if err != nil {
+		return err
+	}
+
+	if pContext.pTag != nil {
+		reference, err = app.tagReference(reference, *pContext.pTag)
+		if err != nil {
+			return err
+		}
+	}
+
+	pContext.pHeader = pHeader
+	pContext.reference = reference
+	pContext.pReferenceKey = pReferenceKey
+	app.closeDictionaries(pContext)
+	pContext.dataOffset = uint(pHeader.length + pHeader.referenceLength)
+	return nil
+}
+
+// fetchReference reads the header and the reference of the database file, then verifies and decrypts the reference
+func (app *application) fetchReference(conn StorageFile, name string, size int64) (*header, references.Reference, *uint, error) {
+	pHeader, err := readHeader(conn, name, size)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = verifyVersion(pHeader, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	referenceDelimiter := pHeader.length + pHeader.referenceLength
+	if uint64(size) < referenceDelimiter {
+		str := fmt.Sprintf("the database (name: %s) was expected to contain at least %d bytes, %d found", name, referenceDelimiter, size)
+		return nil, nil, nil, errors.New(str)
+	}
+
+	referenceBytes := make([]byte, pHeader.referenceLength)
+	_, err = conn.ReadAt(referenceBytes, int64(pHeader.length))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = verifyReference(pHeader, name, referenceBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	referenceBytes, pReferenceKey, err := app.decryptReference(referenceBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reference, err := app.referenceAdapter.ToReference(referenceBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, oneCommit := range reference.Commits().List() {
+		err = app.verifyCommit(oneCommit)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return pHeader, reference, pReferenceKey, nil
+}
+
+// verifyCurrent verifies that the context is locked and that the database on disk did not change since the context
+// read it, so that rewriting the database never drops the changes of another writer
+func (app *application) verifyCurrent(pContext *context) error {
+	err := app.verifyLocked(pContext)
+	if err != nil {
+		return err
+	}
+
+	conn, err := app.storage.Open(filepath.Join(app.dirPath, pContext.name))
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+	size, err := conn.Size()
+	if err != nil {
+		return err
+	}
+
+	if size <= 0 {
+		if pContext.pHeader == nil {
+			return nil
+		}
+
+		str := fmt.Sprintf("the database (name: %s) was emptied since the context (%d) read it", pContext.name, pContext.identifier)
+		return fmt.Errorf("%s: %w", str, ErrConflict)
+	}
+
+	pHeader, reference, _, err := app.fetchReference(conn, pContext.name, size)
+	if err != nil {
+		return err
+	}
+
+	if pContext.pHeader == nil || pContext.reference == nil {
+		str := fmt.Sprintf("the database (name: %s) was committed to since the context (%d) read it", pContext.name, pContext.identifier)
+		return fmt.Errorf("%s: %w", str, ErrConflict)
+	}
+
+	latest := reference.Commits().Latest().Hash()
+	parent := pContext.reference.Commits().Latest().Hash()
+	if !latest.Compare(parent) {
+		str := fmt.Sprintf("the latest commit (hash: %s) of the database (name: %s) is not the parent (hash: %s) the context (%d) commits on", latest.String(), pContext.name, parent.String(), pContext.identifier)
+		return fmt.Errorf("%s: %w", str, ErrConflict)
+	}
+
+	isSameLength := pHeader.referenceLength == pContext.pHeader.referenceLength
+	isSameChecksum := pHeader.pReferenceChecksum != nil && pContext.pHeader.pReferenceChecksum != nil && *pHeader.pReferenceChecksum == *pContext.pHeader.pReferenceChecksum
+	if !isSameLength || !isSameChecksum {
+		str := fmt.Sprintf("the reference of the database (name: %s) was rewritten since the context (%d) read it", pContext.name, pContext.identifier)
+		return fmt.Errorf("%s: %w", str, ErrConflict)
+	}
+
+	return nil
+}
+
+// verifyLocked verifies that the context holds the lock of its database
+func (app *application) verifyLocked(pContext *context) error {
+	if pContext.lock != nil {
+		return nil
+	}
+
+	str := fmt.Sprintf("the context (%d) must be locked in order to write the database (name: %s)", pContext.identifier, pContext.name)
+	return errors.New(str)
+}
+
+// pendingInserts returns a copy of the pending inserts of the context, in insertion order
+func (app *application) pendingInserts(pContext *context) []contents.Content {
+	return append([]contents.Content{}, pContext.insertList...)
+}
+
+// pendingDeletes returns the pending deletes of the context, sorted by kind, then by hash
+func (app *application) pendingDeletes(pContext *context) []references.ContentKey {
+	output := []references.ContentKey{}
+	for _, oneContentKey := range pContext.delList {
+		output = append(output, oneContentKey)
+	}
+
+	return sortContentKeys(output)
+}
+
+func (app *application) fetchContentKey(pContext *context, kind uint, hash hash.Hash) (references.ContentKey, error) {
+	if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+		str := fmt.Sprintf("the database (name: %s) does not contain any content", pContext.name)
+		return nil, errors.New(str)
+	}
+
+	return pContext.reference.ContentKeys().Fetch(kind, hash)
+}
+
+func (app *application) buildCommit(pContext *context, metadata references.Metadata) (references.Commit, error) {
+	actionBuilder := app.referenceActionBuilder.Create()
+	if len(pContext.insertList) > 0 {
+		blocks := [][]byte{}
+		for _, oneContent := range pContext.insertList {
+			blocks = append(blocks, oneContent.Hash().Bytes())
+		}
+
+		insert, err := app.hashTreeBuilder.Create().WithBlocks(blocks).Now()
+		if err != nil {
+			return nil, err
+		}
+
+		actionBuilder.WithInsert(insert)
+	}
+
+	if len(pContext.delList) > 0 {
+		keynames := []string{}
+		for oneKeyname := range pContext.delList {
+			keynames = append(keynames, oneKeyname)
+		}
+
+		sort.Strings(keynames)
+		blocks := [][]byte{}
+		for _, oneKeyname := range keynames {
+			blocks = append(blocks, pContext.delList[oneKeyname].Hash().Bytes())
+		}
+
+		del, err := app.hashTreeBuilder.Create().WithBlocks(blocks).Now()
+		if err != nil {
+			return nil, err
+		}
+
+		actionBuilder.WithDelete(del)
+	}
+
+	action, err := actionBuilder.Now()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := app.referenceCommitBuilder.Create().
+		WithAction(action).
+		CreatedOn(time.Now().UTC())
+
+	if metadata != nil {
+		builder.WithMetadata(metadata)
+	}
+
+	if app.signer != nil {
+		builder.WithSigner(app.signer)
+	}
+
+	if pContext.reference != nil {
+		builder.WithParent(pContext.reference.Commits().Latest().Hash())
+	}
+
+	return builder.Now()
+}
+
+// verifyCommit verifies that the commit is signed when signatures are required, and by a trusted author when
+// there are trusted authors.  The signature itself is verified while the commit is built
+func (app *application) verifyCommit(commit references.Commit) error {
+	if !commit.HasSignature() {
+		if app.isSignatureRequired {
+			return fmt.Errorf("the commit (hash: %s) is rejected: %w", commit.Hash().String(), ErrUnsignedCommit)
+		}
+
+		return nil
+	}
+
+	if len(app.trustedAuthors) <= 0 {
+		return nil
+	}
+
+	author := commit.Signature().Author()
+	if _, ok := app.trustedAuthors[string(author)]; !ok {
+		return fmt.Errorf("the commit (hash: %s) is rejected: %w", commit.Hash().String(), ErrUntrustedCommit)
+	}
+
+	return nil
+}
+
+func (app *application) buildReference(pContext *context, commit references.Commit) (references.Reference, []byte, error) {
+	commitsList := []references.Commit{}
+	contentKeysList := []references.ContentKey{}
+	if pContext.reference != nil {
+		commitsList = append(commitsList, pContext.reference.Commits().List()...)
+		if pContext.reference.HasContentKeys() {
+			for _, oneContentKey := range pContext.reference.ContentKeys().List() {
+				keyname := createKeyname(oneContentKey.Kind(), oneContentKey.Hash())
+				if _, ok := pContext.delList[keyname]; ok {
+					continue
+				}
+
+				contentKeysList = append(contentKeysList, oneContentKey)
+			}
+		}
+	}
+
+	dataLength, err := app.dataLength(pContext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pKey *uint
+	if app.keyProvider != nil {
+		key, err := app.keyProvider.Current()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pKey = &key
+	}
+
+	data := []byte{}
+	for _, oneContent := range pContext.insertList {
+		contentData, codec, err := app.compress(pContext, oneContent.Kind(), oneContent.Data())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if pKey != nil {
+			contentData, err = app.encrypt(*pKey, contentData, createContentAdditionalData(oneContent.Kind(), oneContent.Hash()))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		pointer, err := app.referencePointerBuilder.Create().
+			From(dataLength + uint(len(data))).
+			WithLength(uint(len(contentData))).
+			WithChecksum(computeChecksum(contentData)).
+			Now()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		contentKeyBuilder := app.referenceContentKeyBuilder.Create().
+			WithHash(oneContent.Hash()).
+			WithKind(oneContent.Kind()).
+			WithContent(pointer).
+			WithCommit(commit.Hash()).
+			WithCodec(codec)
+
+		if pKey != nil {
+			contentKeyBuilder.WithKey(*pKey)
+		}
+
+		contentKey, err := contentKeyBuilder.Now()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		contentKeysList = append(contentKeysList, contentKey)
+		data = append(data, contentData...)
+	}
+
+	commits, err := app.referenceCommitsBuilder.Create().
+		WithList(append(commitsList, commit)).
+		Now()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	builder := app.referenceBuilder.Create().WithCommits(commits)
+	if len(contentKeysList) > 0 {
+		contentKeys, err := app.referenceContentKeysBuilder.Create().
+			WithList(contentKeysList).
+			Now()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		builder.WithContentKeys(contentKeys)
+	}
+
+	resources, err := app.updateIndexes(pContext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources, err = app.updateBloomFilters(pContext, resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources, err = app.updateScans(pContext, resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources, err = app.recordDeletes(pContext, commit, resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resources != nil {
+		builder.WithResources(resources)
+	}
+
+	if pContext.reference != nil && pContext.reference.HasTags() {
+		builder.WithTags(pContext.reference.Tags())
+	}
+
+	if pContext.reference != nil && pContext.reference.HasKinds() {
+		builder.WithKinds(pContext.reference.Kinds())
+	}
+
+	reference, err := builder.Now()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reference, data, nil
+}
+
+func (app *application) dataLength(pContext *context) (uint, error) {
+	if pContext.reference == nil {
+		return 0, nil
+	}
+
+	size, err := pContext.conn.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(size) - pContext.dataOffset, nil
+}
+
+// rewrite writes the destination database then replaces the source database of the context by it
+func (app *application) rewrite(pContext *context, referenceBytes []byte, data []byte) error {
+	err := app.verifyCurrent(pContext)
+	if err != nil {
+		return err
+	}
+
+	// a journal left behind by a failed replacement no longer applies once the database is rewritten:
+	err = app.removeJournal(pContext.name)
+	if err != nil {
+		return err
+	}
+
+	destination := app.destinationName(pContext.name)
+	err = app.writeDestination(pContext, destination, referenceBytes, data)
+	if err != nil {
+		app.storage.Remove(filepath.Join(app.dirPath, destination))
+		return err
+	}
+
+	return app.Copy(pContext.identifier, destination)
+}
+
+// writeDestination writes the reference, the existing data then the appended data in the destination database
+func (app *application) writeDestination(pContext *context, destination string, referenceBytes []byte, data []byte) error {
+	destinationPath := filepath.Join(app.dirPath, destination)
+	err := app.storage.Create(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := app.storage.Open(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	header := append(encodeHeader(referenceBytes), referenceBytes...)
+	_, err = file.WriteAt(header, 0)
+	if err != nil {
+		return err
+	}
+
+	// copy the existing data, chunk by chunk:
+	dataLength, err := app.dataLength(pContext)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(len(header))
+	index := uint(0)
+	for index < dataLength {
+		length := app.readChunkSize
+		if index+length > dataLength {
+			length = dataLength - index
+		}
+
+		chunk, err := app.read(pContext.identifier, pContext.dataOffset+index, length)
+		if err != nil {
+			return err
+		}
+
+		_, err = file.WriteAt(chunk, offset+int64(index))
+		if err != nil {
+			return err
+		}
+
+		index += length
+	}
+
+	// append the new data:
+	_, err = file.WriteAt(data, offset+int64(dataLength))
+	if err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// swap replaces the source by the destination using renames only, so that an interruption
+// at any step leaves either the source or the backup in place:
+//  1. the source is moved to the backup,
+//  2. the destination is moved to the source,
+//  3. the renames are made durable,
+//  4. the backup is removed.
+func (app *application) swap(sourcePath string, backupPath string, destinationPath string) error {
+	err := app.storage.Rename(sourcePath, backupPath)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.Rename(destinationPath, sourcePath)
+	if err != nil {
+		// put the source back in place:
+		restoreErr := app.storage.Rename(backupPath, sourcePath)
+		if restoreErr != nil {
+			return restoreErr
+		}
+
+		return err
+	}
+
+	err = app.storage.SyncDir(filepath.Dir(sourcePath))
+	if err != nil {
+		return err
+	}
+
+	return app.storage.Remove(backupPath)
+}
+
+// recover puts the backup back in place when a swap was interrupted after the source was moved, then completes
+// the replacement of the reference when it was interrupted
+func (app *application) recover(name string) error {
+	sourcePath := filepath.Join(app.dirPath, name)
+	sourceExists, err := app.storage.Exists(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	if !sourceExists {
+		backupPath := app.backupPath(name)
+		backupExists, err := app.storage.Exists(backupPath)
+		if err != nil {
+			return err
+		}
+
+		if !backupExists {
+			return nil
+		}
+
+		err = app.storage.Rename(backupPath, sourcePath)
+		if err != nil {
+			return err
+		}
+
+		err = app.storage.SyncDir(filepath.Dir(sourcePath))
+		if err != nil {
+			return err
+		}
+	}
+
+	return app.replayJournal(name)
+}
+
+func (app *application) destinationName(name string) string {
+	return fmt.Sprintf("%s%s%s", name, fileNameExtensionDelimiter, app.dstExtension)
+}
+
+func (app *application) lockPath(name string) string {
+	lockName := fmt.Sprintf("%s%s%s", name, fileNameExtensionDelimiter, lockExtension)
+	return filepath.Join(app.dirPath, lockName)
+}
+
+func (app *application) backupPath(name string) string {
+	backupName := fmt.Sprintf("%s%s%s", name, fileNameExtensionDelimiter, app.bckExtension)
+	return filepath.Join(app.dirPath, backupName)
+}
+
+func createKeyname(kind uint, hash hash.Hash) string {
+	return fmt.Sprintf("%d%s%s", kind, fileNameExtensionDelimiter, hash.String())
+}
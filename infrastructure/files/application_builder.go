@@ -0,0 +1,244 @@
+package files
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	databases "github.com/steve-care-software/databases/applications"
+)
+
+type applicationBuilder struct {
+	storage         Storage
+	dirPath         string
+	dstExtension    string
+	bckExtension    string
+	readChunkSize   uint
+	onOpenFn        databases.OnOpenFn
+	onBeforeFn      databases.OnBeforeCommitFn
+	onAfterFn       databases.OnAfterCommitFn
+	instrumentation databases.Instrumentation
+	keyProvider     KeyProvider
+	compressions    map[uint]uint
+	signer          crypto.Signer
+	authors         []ed25519.PublicKey
+	isSigRequired   bool
+	indexes         []indexDefinition
+	readCache       uint
+	rotationBatch   uint
+}
+
+type indexDefinition struct {
+	kind uint
+	name string
+	fn   IndexFn
+}
+
+func createApplicationBuilder() ApplicationBuilder {
+	out := applicationBuilder{
+		storage:         nil,
+		dirPath:         "",
+		dstExtension:    "",
+		bckExtension:    "",
+		readChunkSize:   0,
+		onOpenFn:        nil,
+		onBeforeFn:      nil,
+		onAfterFn:       nil,
+		instrumentation: nil,
+		keyProvider:     nil,
+		compressions:    map[uint]uint{},
+		signer:          nil,
+		authors:         []ed25519.PublicKey{},
+		isSigRequired:   false,
+		indexes:         []indexDefinition{},
+		readCache:       0,
+		rotationBatch:   0,
+	}
+
+	return &out
+}
+
+// Create initializes the builder
+func (app *applicationBuilder) Create() ApplicationBuilder {
+	return createApplicationBuilder()
+}
+
+// WithStorage adds a storage to the builder
+func (app *applicationBuilder) WithStorage(storage Storage) ApplicationBuilder {
+	app.storage = storage
+	return app
+}
+
+// WithDirPath adds a directory path to the builder
+func (app *applicationBuilder) WithDirPath(dirPath string) ApplicationBuilder {
+	app.dirPath = dirPath
+	return app
+}
+
+// WithDestinationExtension adds a destination extension to the builder
+func (app *applicationBuilder) WithDestinationExtension(dstExtension string) ApplicationBuilder {
+	app.dstExtension = dstExtension
+	return app
+}
+
+// WithBackupExtension adds a backup extension to the builder
+func (app *applicationBuilder) WithBackupExtension(bckExtension string) ApplicationBuilder {
+	app.bckExtension = bckExtension
+	return app
+}
+
+// WithReadChunkSize adds a read chunk size to the builder
+func (app *applicationBuilder) WithReadChunkSize(readChunkSize uint) ApplicationBuilder {
+	app.readChunkSize = readChunkSize
+	return app
+}
+
+// WithOnOpen adds an onOpen func to the builder
+func (app *applicationBuilder) WithOnOpen(onOpenFn databases.OnOpenFn) ApplicationBuilder {
+	app.onOpenFn = onOpenFn
+	return app
+}
+
+// WithOnBeforeCommit adds an onBeforeCommit func to the builder, that can veto the commits
+func (app *applicationBuilder) WithOnBeforeCommit(onBeforeCommitFn databases.OnBeforeCommitFn) ApplicationBuilder {
+	app.onBeforeFn = onBeforeCommitFn
+	return app
+}
+
+// WithOnAfterCommit adds an onAfterCommit func to the builder
+func (app *applicationBuilder) WithOnAfterCommit(onAfterCommitFn databases.OnAfterCommitFn) ApplicationBuilder {
+	app.onAfterFn = onAfterCommitFn
+	return app
+}
+
+// WithInstrumentation adds an instrumentation to the builder, that records the measurements of the operations
+func (app *applicationBuilder) WithInstrumentation(instrumentation databases.Instrumentation) ApplicationBuilder {
+	app.instrumentation = instrumentation
+	return app
+}
+
+// WithKeyProvider adds a key provider to the builder, the contents and reference are then encrypted
+func (app *applicationBuilder) WithKeyProvider(keyProvider KeyProvider) ApplicationBuilder {
+	app.keyProvider = keyProvider
+	return app
+}
+
+// WithCompression compresses the new contents of the kind using the codec
+func (app *applicationBuilder) WithCompression(kind uint, codec uint) ApplicationBuilder {
+	app.compressions[kind] = codec
+	return app
+}
+
+// WithSigner adds an ed25519 signer to the builder, the new commits are then signed
+func (app *applicationBuilder) WithSigner(signer crypto.Signer) ApplicationBuilder {
+	app.signer = signer
+	return app
+}
+
+// WithTrustedAuthors adds trusted authors to the builder, the commits signed by other authors are then rejected
+func (app *applicationBuilder) WithTrustedAuthors(authors []ed25519.PublicKey) ApplicationBuilder {
+	app.authors = authors
+	return app
+}
+
+// RequireSignedCommits flags the builder so that the unsigned commits are rejected
+func (app *applicationBuilder) RequireSignedCommits() ApplicationBuilder {
+	app.isSigRequired = true
+	return app
+}
+
+// WithIndex registers an index on the contents of the kind, maintained on every commit
+func (app *applicationBuilder) WithIndex(kind uint, name string, fn IndexFn) ApplicationBuilder {
+	app.indexes = append(app.indexes, indexDefinition{
+		kind: kind,
+		name: name,
+		fn:   fn,
+	})
+
+	return app
+}
+
+// WithReadCache adds a read cache of the retrieved contents, bounded by the given amount of bytes, to the builder
+func (app *applicationBuilder) WithReadCache(capacity uint) ApplicationBuilder {
+	app.readCache = capacity
+	return app
+}
+
+// WithRotationBatchSize adds the maximum amount of bytes re-encrypted by every batch of a key rotation to the builder
+func (app *applicationBuilder) WithRotationBatchSize(size uint) ApplicationBuilder {
+	app.rotationBatch = size
+	return app
+}
+
+// Now builds a new Application instance
+func (app *applicationBuilder) Now() (Application, error) {
+	if app.storage == nil {
+		return nil, errors.New("the storage is mandatory in order to build an Application instance")
+	}
+
+	if app.dstExtension == "" {
+		return nil, errors.New("the destination extension is mandatory in order to build an Application instance")
+	}
+
+	if app.bckExtension == "" {
+		return nil, errors.New("the backup extension is mandatory in order to build an Application instance")
+	}
+
+	rotationBatchSize := app.rotationBatch
+	if rotationBatchSize <= 0 {
+		rotationBatchSize = defaultRotationBatchSize
+	}
+
+	codecs := createCodecs()
+	for kind, codec := range app.compressions {
+		if _, ok := codecs[codec]; !ok && codec != CodecNone {
+			str := fmt.Sprintf("the compression codec (%d) of the kind (%d) is invalid", codec, kind)
+			return nil, errors.New(str)
+		}
+	}
+
+	indexes := map[uint]map[string]IndexFn{}
+	for _, oneIndex := range app.indexes {
+		if oneIndex.name == "" {
+			str := fmt.Sprintf("the name of the index of the kind (%d) is mandatory in order to build an Application instance", oneIndex.kind)
+			return nil, errors.New(str)
+		}
+
+		if oneIndex.fn == nil {
+			str := fmt.Sprintf("the func of the index (kind: %d, name: %s) is mandatory in order to build an Application instance", oneIndex.kind, oneIndex.name)
+			return nil, errors.New(str)
+		}
+
+		if _, ok := indexes[oneIndex.kind]; !ok {
+			indexes[oneIndex.kind] = map[string]IndexFn{}
+		}
+
+		if _, ok := indexes[oneIndex.kind][oneIndex.name]; ok {
+			str := fmt.Sprintf("the index (kind: %d, name: %s) is registered more than once", oneIndex.kind, oneIndex.name)
+			return nil, errors.New(str)
+		}
+
+		indexes[oneIndex.kind][oneIndex.name] = oneIndex.fn
+	}
+
+	return newApplication(
+		app.storage,
+		app.dirPath,
+		app.dstExtension,
+		app.bckExtension,
+		app.readChunkSize,
+		app.onOpenFn,
+		app.onBeforeFn,
+		app.onAfterFn,
+		app.instrumentation,
+		app.keyProvider,
+		app.compressions,
+		app.signer,
+		app.authors,
+		app.isSigRequired,
+		indexes,
+		app.readCache,
+		rotationBatchSize,
+	)
+}
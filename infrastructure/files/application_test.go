@@ -1,10 +1,84 @@
 package files
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
+	"reflect"
 	"testing"
+	"time"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/applications/conformance"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
 )
 
+func TestApplication_Conformance(t *testing.T) {
+	dirPath := "./test_files"
+	conformance.Execute(t, func() (databases.Application, func()) {
+		app := newApplicationForTests(t, dirPath, nil)
+		return app, func() {
+			os.RemoveAll(dirPath)
+		}
+	})
+}
+
+func TestNewApplication_withZeroReadChunkSize_returnsError(t *testing.T) {
+	_, err := NewApplication("./test_files", "destination", "backup", 0, nil, nil, nil, nil, nil)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	_, err = NewApplicationBuilder().Create().
+		WithStorage(NewOSStorage()).
+		WithDirPath("./test_files").
+		WithDestinationExtension("destination").
+		WithBackupExtension("backup").
+		Now()
+
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestNewApplication_withInvalidExtensions_returnsError(t *testing.T) {
+	invalids := [][]string{
+		{"destination", "destination"},
+		{lockExtension, "backup"},
+		{"destination", journalExtension},
+	}
+
+	for _, oneInvalid := range invalids {
+		_, err := NewApplication("./test_files", oneInvalid[0], oneInvalid[1], 10, nil, nil, nil, nil, nil)
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+
+		_, err = NewApplicationWithStorage(NewOSStorage(), "./test_files", oneInvalid[0], oneInvalid[1], 10, nil, nil, nil, nil, nil)
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+
+		_, err = NewApplicationBuilder().Create().
+			WithStorage(NewOSStorage()).
+			WithDirPath("./test_files").
+			WithDestinationExtension(oneInvalid[0]).
+			WithBackupExtension(oneInvalid[1]).
+			WithReadChunkSize(10).
+			Now()
+
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+	}
+}
+
 func TestExists_thenCreate_thenDelete_Success(t *testing.T) {
 	dirPath := "./test_files"
 	dstExtension := "destination"
@@ -14,7 +88,11 @@ func TestExists_thenCreate_thenDelete_Success(t *testing.T) {
 		os.RemoveAll(dirPath)
 	}()
 
-	database := NewApplication(dirPath, dstExtension, bckExtension, readChunkSize, nil)
+	database, err := NewApplication(dirPath, dstExtension, bckExtension, readChunkSize, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
 
 	name := "my_name"
 	exists, err := database.Exists(name)
@@ -68,3 +146,586 @@ func TestExists_thenCreate_thenDelete_Success(t *testing.T) {
 		return
 	}
 }
+
+func TestInsert_thenCommit_thenRetrieve_thenErase_Success(t *testing.T) {
+	dirPath := "./test_files"
+	dstExtension := "destination"
+	bckExtension := "backup"
+	readChunkSize := uint(10)
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	database, err := NewApplication(dirPath, dstExtension, bckExtension, readChunkSize, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	name := "my_name"
+	err = database.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := database.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = database.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = database.Reference(*pContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(1, []byte("this is the second data"))
+	for _, oneContent := range []contents.Content{first, second} {
+		err = database.Insert(*pContext, oneContent)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+
+	err = database.Insert(*pContext, first)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = database.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = database.Commit(*pContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	third := contents.NewContentForTests(0, []byte("this is the third data"))
+	err = database.Insert(*pContext, third)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = database.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = database.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = database.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err = database.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer database.Close(*pContext)
+
+	reference, err := database.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(reference.Commits().List()) != 2 {
+		t.Errorf("%d commits were expected, %d returned", 2, len(reference.Commits().List()))
+		return
+	}
+
+	if len(reference.ContentKeys().List()) != 2 {
+		t.Errorf("%d contentKeys were expected, %d returned", 2, len(reference.ContentKeys().List()))
+		return
+	}
+
+	_, err = database.Retrieve(*pContext, first.Kind(), first.Hash())
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	for _, oneContent := range []contents.Content{second, third} {
+		retContent, err := database.Retrieve(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(oneContent, retContent) {
+			t.Errorf("the returned content is invalid")
+			return
+		}
+	}
+}
+
+func TestCommit_withHooks_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	// the contents of the kind zero (0) must be valid JSON:
+	deletes := []references.ContentKey{}
+	commits := []references.Commit{}
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithOnBeforeCommit(func(context uint, inserts []contents.Content, pendingDeletes []references.ContentKey) error {
+			for _, oneContent := range inserts {
+				if oneContent.Kind() == 0 && !json.Valid(oneContent.Data()) {
+					return errors.New("the content is not valid JSON")
+				}
+			}
+
+			deletes = append(deletes, pendingDeletes...)
+			return nil
+		}).
+			WithOnAfterCommit(func(context uint, commit references.Commit) {
+				commits = append(commits, commit)
+			})
+	})
+
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	invalid := contents.NewContentForTests(0, []byte("this is not JSON"))
+	err = app.Insert(*pContext, invalid)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	if len(commits) != 0 {
+		t.Errorf("the vetoed commit was expected to NOT be notified")
+		return
+	}
+
+	// the vetoed inserts are still pending:
+	err = app.Insert(*pContext, invalid)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the pending inserts are discarded on close:
+	app.Close(*pContext)
+	valid := contents.NewContentForTests(0, []byte(`{"name": "valid"}`))
+	other := contents.NewContentForTests(1, []byte("this is not JSON but of another kind"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{valid, other}) {
+		return
+	}
+
+	if len(commits) != 1 {
+		t.Errorf("%d commits were expected to be notified, %d notified", 1, len(commits))
+		return
+	}
+
+	pContext, err = app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !commits[0].Hash().Compare(reference.Commits().Latest().Hash()) {
+		t.Errorf("the notified commit was expected to be the latest commit")
+		return
+	}
+
+	// the pending deletes are provided to the before commit func:
+	err = app.Erase(*pContext, valid.Kind(), valid.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(deletes) != 1 || !deletes[0].Hash().Compare(valid.Hash()) {
+		t.Errorf("the deleted content (hash: %s) was expected to be provided", valid.Hash().String())
+		return
+	}
+
+	if len(commits) != 2 {
+		t.Errorf("%d commits were expected to be notified, %d notified", 2, len(commits))
+		return
+	}
+}
+
+func TestLock_thenCommit_thenLockFromAnotherApplication_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	first := newApplicationForTests(t, dirPath, nil)
+	if first == nil {
+		return
+	}
+
+	second := newApplicationForTests(t, dirPath, nil)
+	if second == nil {
+		return
+	}
+
+	executeLockThenCommitThenLockForTests(t, first, second)
+}
+
+func executeLockThenCommitThenLockForTests(t *testing.T, first databases.Application, second databases.Application) {
+	name := "my_name"
+	err := first.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pFirstContext, err := first.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer first.Close(*pFirstContext)
+	err = first.Lock(*pFirstContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = first.Insert(*pFirstContext, contents.NewContentForTests(0, []byte("this is some data")))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = first.Commit(*pFirstContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pSecondContext, err := second.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer second.Close(*pSecondContext)
+	err = second.Lock(*pSecondContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = first.Unlock(*pFirstContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = second.Lock(*pSecondContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+}
+
+func TestCommit_fromStaleContext_returnsConflict_thenRefresh_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	first := newApplicationForTests(t, dirPath, nil)
+	if first == nil {
+		return
+	}
+
+	second := newApplicationForTests(t, dirPath, nil)
+	if second == nil {
+		return
+	}
+
+	name := "my_name"
+	err := first.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, first, name, []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+	}) {
+		return
+	}
+
+	pStaleContext, err := second.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer second.Close(*pStaleContext)
+	if !insertThenCommitForTests(t, first, name, []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the second data")),
+	}) {
+		return
+	}
+
+	err = second.Lock(*pStaleContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	third := contents.NewContentForTests(0, []byte("this is the third data"))
+	err = second.Insert(*pStaleContext, third)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = second.Commit(*pStaleContext)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("the error was expected to be a conflict error, %v returned", err)
+		return
+	}
+
+	err = second.Refresh(*pStaleContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = second.Commit(*pStaleContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := second.Reference(*pStaleContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(reference.Commits().List()) != 3 {
+		t.Errorf("%d commits were expected, %d returned", 3, len(reference.Commits().List()))
+		return
+	}
+
+	if len(reference.ContentKeys().List()) != 3 {
+		t.Errorf("%d content keys were expected, %d returned", 3, len(reference.ContentKeys().List()))
+		return
+	}
+}
+
+func TestCommitWithMetadata_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	content := contents.NewContentForTests(0, []byte("this is some data"))
+	err := app.Insert(*pContext, content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	metadata := references.NewMetadataForTests()
+	err = app.CommitWithMetadata(*pContext, metadata)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	latest := reference.Commits().Latest()
+	if !latest.HasMetadata() {
+		t.Errorf("the commit was expected to contain metadata")
+		return
+	}
+
+	if !reflect.DeepEqual(metadata, latest.Metadata()) {
+		t.Errorf("the returned metadata is invalid")
+		return
+	}
+
+	retrieveOnContextForTests(t, app, *pContext, []contents.Content{content})
+}
+
+func TestInstrumentation_recordsPublicOperations_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	instrumentation := &instrumentationForTests{}
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithInstrumentation(instrumentation)
+	})
+
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	pContext := openThenLockForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	content := contents.NewContentForTests(0, []byte("this is some data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{content}) {
+		return
+	}
+
+	// the reads of the retrieved contents, and of the existing data copied by a commit, are internal:
+	if !retrieveOnContextForTests(t, app, *pContext, []contents.Content{content}) {
+		return
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{contents.NewContentForTests(0, []byte("this is other data"))}) {
+		return
+	}
+
+	_, err := app.Read(*pContext, 0, 1)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Read(*pContext+1, 0, 1)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	expected := []string{
+		"start open my_name", "record open", "end open <nil>",
+		"start lock my_name", "record lock", "end lock <nil>",
+		"start commit my_name", "record commit", "end commit <nil>",
+		"start commit my_name", "record commit", "end commit <nil>",
+		"start read my_name", "record read", "end read <nil>",
+		"start read ", "record read", "end read error",
+	}
+
+	if !reflect.DeepEqual(instrumentation.events, expected) {
+		t.Errorf("the recorded events were expected to be %v, %v returned", expected, instrumentation.events)
+		return
+	}
+}
+
+type instrumentationForTests struct {
+	events []string
+}
+
+type spanForTests struct {
+	instrumentation *instrumentationForTests
+	operation       string
+}
+
+func (obj *instrumentationForTests) StartSpan(operation string, name string) databases.Span {
+	obj.events = append(obj.events, "start "+operation+" "+name)
+	return &spanForTests{
+		instrumentation: obj,
+		operation:       operation,
+	}
+}
+
+func (obj *instrumentationForTests) RecordOperation(operation string, duration time.Duration, amount uint, err error) {
+	obj.events = append(obj.events, "record "+operation)
+}
+
+func (obj *instrumentationForTests) RecordLockWait(duration time.Duration, err error) {
+}
+
+func (obj *instrumentationForTests) RecordCommit(size uint, inserts uint, deletes uint) {
+}
+
+func (obj *spanForTests) End(err error) {
+	outcome := "<nil>"
+	if err != nil {
+		outcome = "error"
+	}
+
+	obj.instrumentation.events = append(obj.instrumentation.events, "end "+obj.operation+" "+outcome)
+}
@@ -0,0 +1,278 @@
+package files
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+// the bloom filters use about 10 bits and 7 positions per hash, for a false positive rate of about 1%
+const bloomResourceKeyword = "bloom"
+const bloomBitsPerHash = 10
+const bloomPositions = 7
+const bloomMinCapacity = 1024
+const bloomHeaderLength = 8 + 8
+
+// bloomFilter represents the bloom filter of the committed hashes of a kind.  The amount contains the hashes added
+// since the filter was built, including the deleted ones, so that the filter is rebuilt once its capacity is reached
+type bloomFilter struct {
+	capacity uint64
+	amount   uint64
+	bits     []byte
+}
+
+func createBloomFilter(capacity uint64) *bloomFilter {
+	if capacity < bloomMinCapacity {
+		capacity = bloomMinCapacity
+	}
+
+	out := bloomFilter{
+		capacity: capacity,
+		amount:   0,
+		bits:     make([]byte, bloomLength(capacity)),
+	}
+
+	return &out
+}
+
+// add adds the hash to the filter
+func (obj *bloomFilter) add(hash hash.Hash) {
+	for _, onePosition := range obj.positions(hash) {
+		obj.bits[onePosition/8] |= 1 << (onePosition % 8)
+	}
+
+	obj.amount++
+}
+
+// mayContain returns false when the hash is definitely absent from the filter, true otherwise
+func (obj *bloomFilter) mayContain(hash hash.Hash) bool {
+	for _, onePosition := range obj.positions(hash) {
+		if obj.bits[onePosition/8]&(1<<(onePosition%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// positions returns the positions of the hash using double hashing.  The hashes are uniformly distributed,
+// therefore their bytes are used as is
+func (obj *bloomFilter) positions(hash hash.Hash) []uint64 {
+	bytes := hash.Bytes()
+	first := binary.LittleEndian.Uint64(bytes[:8])
+	second := binary.LittleEndian.Uint64(bytes[8:16]) | 1
+	length := uint64(len(obj.bits)) * 8
+
+	output := make([]uint64, bloomPositions)
+	for i := range output {
+		output[i] = (first + uint64(i)*second) % length
+	}
+
+	return output
+}
+
+// Has returns true when the committed contents contain the content of the kind, false otherwise.  The bloom filter
+// of the kind answers for the absent contents without looking up their content key
+func (app *application) Has(context uint, kind uint, hash hash.Hash) (bool, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+			return false, nil
+		}
+
+		pFilter, err := app.fetchBloomFilter(pContext, kind)
+		if err != nil {
+			return false, err
+		}
+
+		if pFilter != nil && !pFilter.mayContain(hash) {
+			return false, nil
+		}
+
+		_, err = pContext.reference.ContentKeys().Fetch(kind, hash)
+		return err == nil, nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot execute Has using this context", context)
+	return false, errors.New(str)
+}
+
+// fetchBloomFilter returns the bloom filter of the kind.  Returns nil when the database does not contain it yet, or
+// when the context is opened on a tag, since the stored filters follow the latest commit
+func (app *application) fetchBloomFilter(pContext *context, kind uint) (*bloomFilter, error) {
+	if pContext.pTag != nil || pContext.reference == nil || !pContext.reference.HasResources() {
+		return nil, nil
+	}
+
+	resource, err := pContext.reference.Resources().Fetch(createBloomName(kind))
+	if err != nil {
+		return nil, nil
+	}
+
+	return decodeBloomFilter(resource.Data())
+}
+
+// updateBloomFilters returns the resources in which the bloom filters of the kinds of the pending inserts contain
+// them.  A filter is rebuilt from the content keys when it does not exist yet or when its capacity would be exceeded,
+// which also removes the deleted hashes.  Returns nil when there is no resource
+func (app *application) updateBloomFilters(pContext *context, resources references.Resources) (references.Resources, error) {
+	inserts := map[uint][]hash.Hash{}
+	for _, oneContent := range pContext.insertList {
+		inserts[oneContent.Kind()] = append(inserts[oneContent.Kind()], oneContent.Hash())
+	}
+
+	if len(inserts) <= 0 {
+		return resources, nil
+	}
+
+	kinds := []uint{}
+	for oneKind := range inserts {
+		kinds = append(kinds, oneKind)
+	}
+
+	sort.Slice(kinds, func(i int, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	resourcesList := []references.Resource{}
+	if resources != nil {
+		resourcesList = append(resourcesList, resources.List()...)
+	}
+
+	for _, oneKind := range kinds {
+		pFilter, err := app.fetchBloomFilter(pContext, oneKind)
+		if err != nil {
+			return nil, err
+		}
+
+		if pFilter == nil || pFilter.amount+uint64(len(inserts[oneKind])) > pFilter.capacity {
+			hashes := append(app.committedHashes(pContext, oneKind), inserts[oneKind]...)
+			pFilter = createBloomFilter(uint64(len(hashes)) * 2)
+			for _, oneHash := range hashes {
+				pFilter.add(oneHash)
+			}
+		} else {
+			// the bits are shared with the resource of the current reference:
+			pFilter.bits = append([]byte{}, pFilter.bits...)
+			for _, oneHash := range inserts[oneKind] {
+				pFilter.add(oneHash)
+			}
+		}
+
+		resource, err := app.referenceResourceBuilder.Create().
+			WithName(createBloomName(oneKind)).
+			WithData(encodeBloomFilter(pFilter)).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		resourcesList = replaceResource(resourcesList, resource)
+	}
+
+	return app.referenceResourcesBuilder.Create().
+		WithList(resourcesList).
+		Now()
+}
+
+// rebuildBloomFilters returns the resources in which the bloom filter of every kind of the content keys is rebuilt,
+// when the database is rewritten
+func (app *application) rebuildBloomFilters(contentKeysList []references.ContentKey, resourcesList []references.Resource) ([]references.Resource, error) {
+	hashes := map[uint][]hash.Hash{}
+	kinds := []uint{}
+	for _, oneContentKey := range contentKeysList {
+		kind := oneContentKey.Kind()
+		if _, ok := hashes[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+
+		hashes[kind] = append(hashes[kind], oneContentKey.Hash())
+	}
+
+	sort.Slice(kinds, func(i int, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	for _, oneKind := range kinds {
+		pFilter := createBloomFilter(uint64(len(hashes[oneKind])) * 2)
+		for _, oneHash := range hashes[oneKind] {
+			pFilter.add(oneHash)
+		}
+
+		resource, err := app.referenceResourceBuilder.Create().
+			WithName(createBloomName(oneKind)).
+			WithData(encodeBloomFilter(pFilter)).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		resourcesList = replaceResource(resourcesList, resource)
+	}
+
+	return resourcesList, nil
+}
+
+// committedHashes returns the hashes of the committed contents of the kind, without the pending deletes
+func (app *application) committedHashes(pContext *context, kind uint) []hash.Hash {
+	output := []hash.Hash{}
+	if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+		return output
+	}
+
+	// the kind does not contain any content:
+	contentKeys, err := pContext.reference.ContentKeys().ListByKind(kind)
+	if err != nil {
+		return output
+	}
+
+	for _, oneContentKey := range contentKeys {
+		if _, ok := pContext.delList[createKeyname(kind, oneContentKey.Hash())]; ok {
+			continue
+		}
+
+		output = append(output, oneContentKey.Hash())
+	}
+
+	return output
+}
+
+func encodeBloomFilter(filter *bloomFilter) []byte {
+	output := binary.LittleEndian.AppendUint64([]byte{}, filter.capacity)
+	output = binary.LittleEndian.AppendUint64(output, filter.amount)
+	return append(output, filter.bits...)
+}
+
+func decodeBloomFilter(data []byte) (*bloomFilter, error) {
+	if len(data) < bloomHeaderLength {
+		str := fmt.Sprintf("the bloom filter was expected to contain at least %d bytes, %d provided", bloomHeaderLength, len(data))
+		return nil, errors.New(str)
+	}
+
+	capacity := binary.LittleEndian.Uint64(data[:8])
+	expected := bloomHeaderLength + bloomLength(capacity)
+	if capacity < bloomMinCapacity || uint64(len(data)) != expected {
+		str := fmt.Sprintf("the bloom filter (capacity: %d) was expected to contain %d bytes, %d provided", capacity, expected, len(data))
+		return nil, errors.New(str)
+	}
+
+	return &bloomFilter{
+		capacity: capacity,
+		amount:   binary.LittleEndian.Uint64(data[8:bloomHeaderLength]),
+		bits:     data[bloomHeaderLength:],
+	}, nil
+}
+
+func bloomLength(capacity uint64) uint64 {
+	return (capacity*bloomBitsPerHash + 7) / 8
+}
+
+func createBloomName(kind uint) string {
+	return fmt.Sprintf("%s%s%d", bloomResourceKeyword, fileNameExtensionDelimiter, kind)
+}
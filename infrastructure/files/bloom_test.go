@@ -0,0 +1,297 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+func TestBloomFilter_Success(t *testing.T) {
+	filter := createBloomFilter(0)
+	added := []hash.Hash{}
+	for i := 0; i < bloomMinCapacity; i++ {
+		pHash, err := hash.NewAdapter().FromBytes([]byte(fmt.Sprintf("this is the added data %d", i)))
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		filter.add(*pHash)
+		added = append(added, *pHash)
+	}
+
+	for _, oneHash := range added {
+		if !filter.mayContain(oneHash) {
+			t.Errorf("the filter was expected to contain the added hash (%s)", oneHash.String())
+			return
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		pHash, err := hash.NewAdapter().FromBytes([]byte(fmt.Sprintf("this is the absent data %d", i)))
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if filter.mayContain(*pHash) {
+			falsePositives++
+		}
+	}
+
+	if falsePositives > 300 {
+		t.Errorf("the filter was expected to contain at most %d false positives, %d returned", 300, falsePositives)
+		return
+	}
+
+	retFilter, err := decodeBloomFilter(encodeBloomFilter(filter))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(filter, retFilter) {
+		t.Errorf("the decoded filter is invalid")
+		return
+	}
+
+	_, err = decodeBloomFilter(encodeBloomFilter(filter)[1:])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestHas_rebuildsFilter_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// commit more contents than the capacity of the first filter, in two commits:
+	first := []contents.Content{}
+	for i := 0; i < bloomMinCapacity; i++ {
+		first = append(first, contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the first data %d", i))))
+	}
+
+	second := []contents.Content{}
+	for i := 0; i < 10; i++ {
+		second = append(second, contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the second data %d", i))))
+	}
+
+	if !insertThenCommitForTests(t, app, name, first[:bloomMinCapacity/2]) {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, append(first[bloomMinCapacity/2:], second...)) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	pFilter, err := app.(*application).fetchBloomFilter(app.(*application).contexts[*pContext], 0)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	expected := uint64(bloomMinCapacity+len(second)) * 2
+	if pFilter == nil || pFilter.capacity != expected {
+		t.Errorf("the filter was expected to be rebuilt using a capacity of %d", expected)
+		return
+	}
+
+	for _, oneContent := range append(first, second...) {
+		has, err := app.Has(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !has {
+			t.Errorf("the content (hash: %s) was expected to exist", oneContent.Hash().String())
+			return
+		}
+	}
+}
+
+func TestHas_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	_, err := app.Has(0, first.Kind(), first.Hash())
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	has, err := app.Has(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if has {
+		t.Errorf("the empty database was expected to NOT contain the content")
+		return
+	}
+
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	third := contents.NewContentForTests(1, []byte("this is the third data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first, second}) {
+		return
+	}
+
+	expected := map[contents.Content]bool{first: true, second: true, third: false}
+	for oneContent, isExpected := range expected {
+		has, err := app.Has(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if has != isExpected {
+			t.Errorf("the content (kind: %d) was expected to exist: %t, returned: %t", oneContent.Kind(), isExpected, has)
+			return
+		}
+	}
+
+	// the content of the same hash in another kind does not exist:
+	has, err = app.Has(*pContext, 1, first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if has {
+		t.Errorf("the content was expected to NOT exist in another kind")
+		return
+	}
+
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	has, err = app.Has(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if has {
+		t.Errorf("the erased content was expected to NOT exist")
+		return
+	}
+}
+
+func TestHas_withAbsentFromFilter_doesNotFetchContentKey_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	content := contents.NewContentForTests(0, []byte("this is some data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{content}) {
+		return
+	}
+
+	// replace the filter of the kind by an empty one, while the content keys still contain the content:
+	filesApp := app.(*application)
+	pAppContext := filesApp.contexts[*pContext]
+	resource, err := filesApp.referenceResourceBuilder.Create().
+		WithName(createBloomName(content.Kind())).
+		WithData(encodeBloomFilter(createBloomFilter(0))).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	resources, err := filesApp.referenceResourcesBuilder.Create().
+		WithList(replaceResource(pAppContext.reference.Resources().List(), resource)).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := filesApp.referenceBuilder.Create().
+		WithCommits(pAppContext.reference.Commits()).
+		WithContentKeys(pAppContext.reference.ContentKeys()).
+		WithResources(resources).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pAppContext.reference = reference
+	has, err := app.Has(*pContext, content.Kind(), content.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if has {
+		t.Errorf("the filter was expected to answer for the absent content without fetching its content key")
+		return
+	}
+}
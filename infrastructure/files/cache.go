@@ -0,0 +1,107 @@
+package files
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// readCache represents a least recently used cache of the retrieved contents, bounded by the size of their data.
+// The contents are immutable, and their keys contain the commit that inserted them, therefore the cache is never
+// invalidated: the entries that cannot be retrieved anymore are evicted as the other ones are used
+type readCache struct {
+	mutex     sync.Mutex
+	capacity  uint
+	size      uint
+	entries   map[string]*list.Element
+	order     *list.List
+	hits      uint
+	misses    uint
+	evictions uint
+}
+
+type readCacheEntry struct {
+	key  string
+	data []byte
+}
+
+func createReadCache(capacity uint) *readCache {
+	out := readCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+
+	return &out
+}
+
+// get returns a copy of the cached data, if any
+func (obj *readCache) get(key string) ([]byte, bool) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if element, ok := obj.entries[key]; ok {
+		obj.order.MoveToFront(element)
+		obj.hits++
+		return append([]byte{}, element.Value.(*readCacheEntry).data...), true
+	}
+
+	obj.misses++
+	return nil, false
+}
+
+// put adds a copy of the data to the cache, then evicts the least recently used entries until it fits.  The data
+// larger than the capacity is not cached
+func (obj *readCache) put(key string, data []byte) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	length := uint(len(data))
+	if length > obj.capacity {
+		return
+	}
+
+	if _, ok := obj.entries[key]; ok {
+		return
+	}
+
+	obj.entries[key] = obj.order.PushFront(&readCacheEntry{
+		key:  key,
+		data: append([]byte{}, data...),
+	})
+
+	obj.size += length
+	for obj.size > obj.capacity {
+		oldest := obj.order.Back()
+		entry := obj.order.Remove(oldest).(*readCacheEntry)
+		delete(obj.entries, entry.key)
+		obj.size -= uint(len(entry.data))
+		obj.evictions++
+	}
+}
+
+func (obj *readCache) stats() CacheStats {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	return CacheStats{
+		Hits:      obj.hits,
+		Misses:    obj.misses,
+		Evictions: obj.evictions,
+		Length:    uint(len(obj.entries)),
+		Size:      obj.size,
+		Capacity:  obj.capacity,
+	}
+}
+
+// CacheStats returns the statistics of the read cache, empty when there is no read cache
+func (app *application) CacheStats() CacheStats {
+	if app.readCache == nil {
+		return CacheStats{}
+	}
+
+	return app.readCache.stats()
+}
+
+func createReadCacheKey(name string, contentKey references.ContentKey) string {
+	return fmt.Sprintf("%s%s%s%s%s", name, fileNameExtensionDelimiter, createKeyname(contentKey.Kind(), contentKey.Hash()), fileNameExtensionDelimiter, contentKey.Commit().String())
+}
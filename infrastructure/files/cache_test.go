@@ -0,0 +1,159 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestReadCache_Success(t *testing.T) {
+	cache := createReadCache(10)
+	cache.put("first", []byte("1111"))
+	cache.put("second", []byte("2222"))
+
+	data, ok := cache.get("first")
+	if !ok || string(data) != "1111" {
+		t.Errorf("the first data was expected to be cached")
+		return
+	}
+
+	// the returned data is a copy:
+	data[0] = '0'
+
+	// the second data is the least recently used:
+	cache.put("third", []byte("3333"))
+	_, ok = cache.get("second")
+	if ok {
+		t.Errorf("the second data was expected to be evicted")
+		return
+	}
+
+	data, ok = cache.get("first")
+	if !ok || string(data) != "1111" {
+		t.Errorf("the first data was expected to be cached, unmodified")
+		return
+	}
+
+	// the data larger than the capacity is not cached:
+	cache.put("fourth", []byte("44444444444"))
+	_, ok = cache.get("fourth")
+	if ok {
+		t.Errorf("the fourth data was expected to NOT be cached")
+		return
+	}
+
+	expected := CacheStats{
+		Hits:      2,
+		Misses:    2,
+		Evictions: 1,
+		Length:    2,
+		Size:      8,
+		Capacity:  10,
+	}
+
+	if stats := cache.stats(); stats != expected {
+		t.Errorf("the stats were expected to be %v, %v returned", expected, stats)
+		return
+	}
+}
+
+func TestReadCache_isConcurrent_Success(t *testing.T) {
+	cache := createReadCache(64)
+	group := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func(index int) {
+			defer group.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d", (index+j)%20)
+				if _, ok := cache.get(key); !ok {
+					cache.put(key, []byte("12345678"))
+				}
+			}
+		}(i)
+	}
+
+	group.Wait()
+	stats := cache.stats()
+	if stats.Hits+stats.Misses != 800 || stats.Size > stats.Capacity || stats.Size != stats.Length*8 {
+		t.Errorf("the stats are invalid: %v", stats)
+		return
+	}
+}
+
+func TestRetrieve_withReadCache_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithReadCache(1024)
+	})
+
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+
+	stats := app.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Length != 2 {
+		t.Errorf("the cache was expected to contain 2 hits, 2 misses and 2 contents, %v returned", stats)
+		return
+	}
+
+	// an erased content is not retrieved from the cache:
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Retrieve(*pContext, first.Kind(), first.Hash())
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
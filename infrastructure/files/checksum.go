@@ -0,0 +1,41 @@
+package files
+
+import (
+	"fmt"
+	"hash/crc32"
+
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// Error returns the error message
+func (obj *CorruptedError) Error() string {
+	return fmt.Sprintf("the content (kind: %d, hash: %s), at offset %d, does not match its checksum: %s", obj.Kind, obj.Hash.String(), obj.Offset, ErrCorrupted.Error())
+}
+
+// Unwrap returns ErrCorrupted
+func (obj *CorruptedError) Unwrap() error {
+	return ErrCorrupted
+}
+
+// computeChecksum returns the CRC32C checksum of the data
+func computeChecksum(data []byte) uint32 {
+	return crc32.Checksum(data, crc32cTable)
+}
+
+// verifyContent returns a CorruptedError when the content, as stored at the offset, does not match the checksum of its pointer
+func verifyContent(contentKey references.ContentKey, offset uint, data []byte) error {
+	pointer := contentKey.Content()
+	if !pointer.HasChecksum() {
+		return nil
+	}
+
+	if *pointer.Checksum() != computeChecksum(data) {
+		return &CorruptedError{
+			Kind:   contentKey.Kind(),
+			Hash:   contentKey.Hash(),
+			Offset: offset,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package files
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestRetrieve_withCorruptedContent_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(1, []byte("this is the second data"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+
+	// flip a bit of the last content:
+	path := filepath.Join(dirPath, name)
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	corrupted := append([]byte{}, current...)
+	corrupted[len(corrupted)-1] ^= 1
+	err = os.WriteFile(path, corrupted, filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, []contents.Content{first}) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	_, err = app.Retrieve(*pContext, second.Kind(), second.Hash())
+	if !errors.Is(err, ErrCorrupted) {
+		t.Errorf("the error was expected to be ErrCorrupted, %v returned", err)
+		return
+	}
+
+	var corruptedErr *CorruptedError
+	if !errors.As(err, &corruptedErr) {
+		t.Errorf("the error was expected to be a CorruptedError, %v returned", err)
+		return
+	}
+
+	if corruptedErr.Kind != second.Kind() {
+		t.Errorf("the kind was expected to be %d, %d returned", second.Kind(), corruptedErr.Kind)
+		return
+	}
+
+	if !corruptedErr.Hash.Compare(second.Hash()) {
+		t.Errorf("the hash was expected to be %s, %s returned", second.Hash().String(), corruptedErr.Hash.String())
+		return
+	}
+
+	expectedOffset := uint(len(corrupted) - len(second.Data()))
+	if corruptedErr.Offset != expectedOffset {
+		t.Errorf("the offset was expected to be %d, %d returned", expectedOffset, corruptedErr.Offset)
+		return
+	}
+}
+
+func TestOpen_withCorruptedReference_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, []contents.Content{contents.NewContentForTests(0, []byte("this is some data"))}) {
+		return
+	}
+
+	// flip a bit of the reference:
+	path := filepath.Join(dirPath, name)
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	corrupted := append([]byte{}, current...)
+	corrupted[headerLength] ^= 1
+	err = os.WriteFile(path, corrupted, filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Open(name)
+	if !errors.Is(err, ErrCorrupted) {
+		t.Errorf("the error was expected to be ErrCorrupted, %v returned", err)
+		return
+	}
+}
@@ -0,0 +1,178 @@
+package files
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+type codec interface {
+	compress(data []byte) ([]byte, error)
+	decompress(data []byte) ([]byte, error)
+}
+
+func createCodecs() map[uint]codec {
+	return map[uint]codec{
+		CodecGzip:   &gzipCodec{},
+		CodecZstd:   &zstdCodec{},
+		CodecSnappy: &snappyCodec{},
+	}
+}
+
+// compress compresses the data using the dictionary of the kind if the database contains one, otherwise
+// using the codec of the kind, if any.  The data is kept uncompressed when compressing it does not make it smaller.
+// Returns the data and the codec it is compressed with
+func (app *application) compress(pContext *context, kind uint, data []byte) ([]byte, uint, error) {
+	var codec codec
+	codecID, ok := app.compressions[kind]
+	if ok {
+		codec = app.fetchCodec(codecID)
+	}
+
+	dictionaryCodec, err := app.fetchDictionaryCodec(pContext, kind)
+	if err != nil {
+		return nil, CodecNone, err
+	}
+
+	if dictionaryCodec != nil {
+		codec = dictionaryCodec
+		codecID = CodecZstdDictionary
+	}
+
+	if codec == nil {
+		return data, CodecNone, nil
+	}
+
+	compressed, err := codec.compress(data)
+	if err != nil {
+		return nil, CodecNone, err
+	}
+
+	if len(compressed) >= len(data) {
+		return data, CodecNone, nil
+	}
+
+	return compressed, codecID, nil
+}
+
+// decompress decompresses the data of a content using the codec of its content key
+func (app *application) decompress(pContext *context, contentKey references.ContentKey, data []byte) ([]byte, error) {
+	codecID := contentKey.Codec()
+	if codecID == CodecNone {
+		return data, nil
+	}
+
+	codec := app.fetchCodec(codecID)
+	if codecID == CodecZstdDictionary {
+		dictionaryCodec, err := app.fetchDictionaryCodec(pContext, contentKey.Kind())
+		if err != nil {
+			return nil, err
+		}
+
+		if dictionaryCodec != nil {
+			codec = dictionaryCodec
+		}
+	}
+
+	if codec == nil {
+		str := fmt.Sprintf("the content (kind: %d, hash: %s) is compressed using an unknown codec: %d", contentKey.Kind(), contentKey.Hash().String(), codecID)
+		return nil, errors.New(str)
+	}
+
+	return codec.decompress(data)
+}
+
+func (app *application) fetchCodec(codecID uint) codec {
+	if codec, ok := app.codecs[codecID]; ok {
+		return codec
+	}
+
+	return nil
+}
+
+type gzipCodec struct {
+}
+
+func (obj *gzipCodec) compress(data []byte) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (obj *gzipCodec) decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// zstdCodec lazily creates its encoder and decoder, which are expensive to create but safe for concurrent use
+type zstdCodec struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	err     error
+}
+
+func (obj *zstdCodec) init() error {
+	obj.once.Do(func() {
+		obj.encoder, obj.err = zstd.NewWriter(nil)
+		if obj.err != nil {
+			return
+		}
+
+		obj.decoder, obj.err = zstd.NewReader(nil)
+	})
+
+	return obj.err
+}
+
+func (obj *zstdCodec) compress(data []byte) ([]byte, error) {
+	err := obj.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.encoder.EncodeAll(data, nil), nil
+}
+
+func (obj *zstdCodec) decompress(data []byte) ([]byte, error) {
+	err := obj.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.decoder.DecodeAll(data, nil)
+}
+
+// snappyCodec uses the snappy compatible block format of s2
+type snappyCodec struct {
+}
+
+func (obj *snappyCodec) compress(data []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, data), nil
+}
+
+func (obj *snappyCodec) decompress(data []byte) ([]byte, error) {
+	return s2.Decode(nil, data)
+}
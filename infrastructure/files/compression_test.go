@@ -0,0 +1,186 @@
+package files
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/applications/conformance"
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestApplication_withCompression_Conformance(t *testing.T) {
+	dirPath := "./test_files"
+	conformance.Execute(t, func() (databases.Application, func()) {
+		app := newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{
+			0: CodecGzip,
+			1: CodecZstd,
+		}, nil))
+
+		return app, func() {
+			os.RemoveAll(dirPath)
+		}
+	})
+}
+
+func TestApplication_withCompression_perCodec_Success(t *testing.T) {
+	codecs := map[string]uint{
+		"gzip":   CodecGzip,
+		"zstd":   CodecZstd,
+		"snappy": CodecSnappy,
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			dirPath := "./test_files"
+			defer func() {
+				os.RemoveAll(dirPath)
+			}()
+
+			data := bytes.Repeat([]byte(`{"name":"my_name","description":"this is a description"}`), 100)
+			list := []contents.Content{
+				contents.NewContentForTests(0, data),
+				contents.NewContentForTests(1, data),
+			}
+
+			app := newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{
+				0: codec,
+			}, NewKeyProvider([]byte("this is a 32 bytes long key....."))))
+			if app == nil {
+				return
+			}
+
+			err := app.New("my_name")
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			if !insertThenCommitForTests(t, app, "my_name", list) {
+				return
+			}
+
+			if !retrieveAllForTests(t, app, "my_name", list) {
+				return
+			}
+
+			// only the contents of the first kind are compressed:
+			info, err := os.Stat(filepath.Join(dirPath, "my_name"))
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			if info.Size() >= int64(len(data)*2) {
+				t.Errorf("the database was expected to contain less than %d bytes, %d returned", len(data)*2, info.Size())
+				return
+			}
+		})
+	}
+}
+
+func TestApplication_withCompression_thenChangeCodec_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	first := contents.NewContentForTests(0, bytes.Repeat([]byte("this is the first data"), 100))
+	second := contents.NewContentForTests(0, bytes.Repeat([]byte("this is the second data"), 100))
+	app := newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{
+		0: CodecGzip,
+	}, nil))
+	if app == nil {
+		return
+	}
+
+	err := app.New("my_name")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, "my_name", []contents.Content{first}) {
+		return
+	}
+
+	// the codec is recorded per content, so the previous contents remain readable:
+	app = newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{
+		0: CodecSnappy,
+	}, nil))
+	if app == nil {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, "my_name", []contents.Content{second}) {
+		return
+	}
+
+	if !retrieveAllForTests(t, app, "my_name", []contents.Content{first, second}) {
+		return
+	}
+
+	pContext, err := app.Open("my_name")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	firstContentKey, err := reference.ContentKeys().Fetch(0, first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if firstContentKey.Codec() != CodecGzip {
+		t.Errorf("the codec was expected to be %d, %d returned", CodecGzip, firstContentKey.Codec())
+		return
+	}
+
+	secondContentKey, err := reference.ContentKeys().Fetch(0, second.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if secondContentKey.Codec() != CodecSnappy {
+		t.Errorf("the codec was expected to be %d, %d returned", CodecSnappy, secondContentKey.Codec())
+		return
+	}
+}
+
+func TestApplicationBuilder_withInvalidCodec_returnsError(t *testing.T) {
+	_, err := NewApplicationBuilder().Create().
+		WithStorage(NewOSStorage()).
+		WithDestinationExtension("destination").
+		WithBackupExtension("backup").
+		WithReadChunkSize(10).
+		WithCompression(0, 45).
+		Now()
+
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func withCompressionsForTests(compressions map[uint]uint, keyProvider KeyProvider) func(builder ApplicationBuilder) {
+	return func(builder ApplicationBuilder) {
+		if keyProvider != nil {
+			builder.WithKeyProvider(keyProvider)
+		}
+
+		for kind, codec := range compressions {
+			builder.WithCompression(kind, codec)
+		}
+	}
+}
@@ -1,20 +1,35 @@
 package files
 
 import (
-	"os"
-
-	"github.com/juju/fslock"
 	"github.com/steve-care-software/databases/domain/contents"
 	"github.com/steve-care-software/databases/domain/references"
 )
 
 type context struct {
-	identifier uint
-	name       string
-	pLock      *fslock.Lock
-	pConn      *os.File
-	reference  references.Reference
-	dataOffset uint
-	insertList []contents.Content
-	delList    map[string]references.ContentKey
+	identifier    uint
+	name          string
+	lock          StorageLock
+	conn          StorageFile
+	pHeader       *header
+	reference     references.Reference
+	pReferenceKey *uint
+	dataOffset    uint
+	insertList    []contents.Content
+	delList       map[string]references.ContentKey
+
+	// the name of the tag the context is opened on, if any.  Such a context is read-only:
+	pTag *string
+
+	// true when the file is mapped in memory, the reads being served from the mapping.  Such a context is read-only:
+	isMapped bool
+	mapping  []byte
+
+	// the mappings replaced when the context was refreshed, unmapped once the context is closed:
+	superseded [][]byte
+
+	// the amount of commits detected on the database by the watchers when the context was last refreshed:
+	changes uint
+
+	// the codecs built from the dictionaries of the reference, by kind:
+	dictionaries map[uint]*dictionaryCodec
 }
@@ -0,0 +1,248 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+const dictionaryResourceKeyword = "dictionary"
+const dictionaryMaxLength = 16 * 1024
+const dictionarySamplesMaxLength = 64 * dictionaryMaxLength
+const dictionarySampleMinLength = 8
+const dictionaryHashLength = 6
+
+// dictionaryCodec compresses using the latest dictionary of a kind and decompresses using any dictionary of the kind.
+// The dictionaries are zstd raw content dictionaries, their version being recorded as the dictionary identifier of the zstd frames
+type dictionaryCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func (obj *dictionaryCodec) compress(data []byte) ([]byte, error) {
+	return obj.encoder.EncodeAll(data, nil), nil
+}
+
+func (obj *dictionaryCodec) decompress(data []byte) ([]byte, error) {
+	return obj.decoder.DecodeAll(data, nil)
+}
+
+// TrainDictionary trains a new compression dictionary on samples of the committed contents of the kind.  The dictionary
+// is a resource of the reference rather than a content, therefore it is stored without a commit: the commit callbacks
+// are not executed and the subscribers are not notified
+func (app *application) TrainDictionary(context uint, kind uint) error {
+	if pContext, ok := app.contexts[context]; ok {
+		err := app.verifyWritable(pContext)
+		if err != nil {
+			return err
+		}
+
+		if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+			str := fmt.Sprintf("the database (name: %s) does not contain any content and therefore cannot train a dictionary", pContext.name)
+			return errors.New(str)
+		}
+
+		contentKeys, err := pContext.reference.ContentKeys().ListByKind(kind)
+		if err != nil {
+			return err
+		}
+
+		// sample the latest contents of the kind, the contents too short to contain a match being skipped:
+		samples := [][]byte{}
+		samplesLength := 0
+		for index := len(contentKeys) - 1; index >= 0; index-- {
+			if samplesLength >= dictionarySamplesMaxLength {
+				break
+			}
+
+			content, err := app.Retrieve(context, kind, contentKeys[index].Hash())
+			if err != nil {
+				return err
+			}
+
+			if len(content.Data()) < dictionarySampleMinLength {
+				continue
+			}
+
+			samples = append(samples, content.Data())
+			samplesLength += len(content.Data())
+		}
+
+		if len(samples) <= 0 {
+			str := fmt.Sprintf("the kind (%d) of the database (name: %s) does not contain any content of at least %d bytes and therefore cannot train a dictionary", kind, pContext.name, dictionarySampleMinLength)
+			return errors.New(str)
+		}
+
+		dictionary, err := dict.BuildRawDict(samples, dict.Options{
+			MaxDictSize: dictionaryMaxLength,
+			HashBytes:   dictionaryHashLength,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		versions, _ := app.fetchDictionaries(pContext, kind)
+		version := uint(1)
+		if len(versions) > 0 {
+			version = versions[len(versions)-1] + 1
+		}
+
+		resource, err := app.referenceResourceBuilder.Create().
+			WithName(createDictionaryName(kind, version)).
+			WithData(dictionary).
+			Now()
+
+		if err != nil {
+			return err
+		}
+
+		resourcesList := []references.Resource{}
+		if pContext.reference.HasResources() {
+			resourcesList = append(resourcesList, pContext.reference.Resources().List()...)
+		}
+
+		resources, err := app.referenceResourcesBuilder.Create().
+			WithList(append(resourcesList, resource)).
+			Now()
+
+		if err != nil {
+			return err
+		}
+
+		builder := app.referenceBuilder.Create().
+			WithCommits(pContext.reference.Commits()).
+			WithContentKeys(pContext.reference.ContentKeys()).
+			WithResources(resources)
+
+		if pContext.reference.HasTags() {
+			builder.WithTags(pContext.reference.Tags())
+		}
+
+		if pContext.reference.HasKinds() {
+			builder.WithKinds(pContext.reference.Kinds())
+		}
+
+		reference, err := builder.Now()
+
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err := app.referenceAdapter.ToContent(reference)
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err = app.encryptReference(referenceBytes)
+		if err != nil {
+			return err
+		}
+
+		return app.rewrite(pContext, referenceBytes, []byte{})
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot train a dictionary using this context", context)
+	return errors.New(str)
+}
+
+// fetchDictionaryCodec returns the codec built from the dictionaries of the kind, nil if the kind has no dictionary
+func (app *application) fetchDictionaryCodec(pContext *context, kind uint) (codec, error) {
+	if codec, ok := pContext.dictionaries[kind]; ok {
+		return codec, nil
+	}
+
+	versions, dictionaries := app.fetchDictionaries(pContext, kind)
+	if len(dictionaries) <= 0 {
+		return nil, nil
+	}
+
+	latest := len(dictionaries) - 1
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderDictRaw(uint32(versions[latest]), dictionaries[latest]))
+	if err != nil {
+		return nil, err
+	}
+
+	decoderOptions := []zstd.DOption{}
+	for index, oneDictionary := range dictionaries {
+		decoderOptions = append(decoderOptions, zstd.WithDecoderDictRaw(uint32(versions[index]), oneDictionary))
+	}
+
+	decoder, err := zstd.NewReader(nil, decoderOptions...)
+	if err != nil {
+		encoder.Close()
+		return nil, err
+	}
+
+	codec := &dictionaryCodec{
+		encoder: encoder,
+		decoder: decoder,
+	}
+
+	if pContext.dictionaries == nil {
+		pContext.dictionaries = map[uint]*dictionaryCodec{}
+	}
+
+	pContext.dictionaries[kind] = codec
+	return codec, nil
+}
+
+// fetchDictionaries returns the versions and dictionaries of the kind, sorted by version
+func (app *application) fetchDictionaries(pContext *context, kind uint) ([]uint, [][]byte) {
+	if pContext.reference == nil || !pContext.reference.HasResources() {
+		return []uint{}, [][]byte{}
+	}
+
+	byVersion := map[uint][]byte{}
+	versions := []uint{}
+	prefix := createDictionaryPrefix(kind)
+	for _, oneResource := range pContext.reference.Resources().List() {
+		name := oneResource.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		version, err := strconv.ParseUint(strings.TrimPrefix(name, prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		byVersion[uint(version)] = oneResource.Data()
+		versions = append(versions, uint(version))
+	}
+
+	sort.Slice(versions, func(i int, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	dictionaries := [][]byte{}
+	for _, oneVersion := range versions {
+		dictionaries = append(dictionaries, byVersion[oneVersion])
+	}
+
+	return versions, dictionaries
+}
+
+// closeDictionaries releases the codecs built from the dictionaries of the context
+func (app *application) closeDictionaries(pContext *context) {
+	for _, oneCodec := range pContext.dictionaries {
+		oneCodec.encoder.Close()
+		oneCodec.decoder.Close()
+	}
+
+	pContext.dictionaries = map[uint]*dictionaryCodec{}
+}
+
+func createDictionaryName(kind uint, version uint) string {
+	return fmt.Sprintf("%s%d", createDictionaryPrefix(kind), version)
+}
+
+func createDictionaryPrefix(kind uint) string {
+	return fmt.Sprintf("%s%s%d%s", dictionaryResourceKeyword, fileNameExtensionDelimiter, kind, fileNameExtensionDelimiter)
+}
@@ -0,0 +1,269 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+func TestTrainDictionary_thenInsert_thenTrainAgain_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{}, NewKeyProvider([]byte("this is a 32 bytes long key....."))))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	samples := newStructuredContentsForTests(0, 0, 200)
+	if !insertThenCommitForTests(t, app, name, samples) {
+		return
+	}
+
+	// train the first dictionary, then compress new contents with it:
+	if !trainDictionaryForTests(t, app, name, 0) {
+		return
+	}
+
+	first := newStructuredContentsForTests(0, 200, 20)
+	if !insertThenCommitForTests(t, app, name, first) {
+		return
+	}
+
+	// train the second dictionary, then compress new contents with it:
+	if !trainDictionaryForTests(t, app, name, 0) {
+		return
+	}
+
+	second := newStructuredContentsForTests(0, 220, 20)
+	if !insertThenCommitForTests(t, app, name, second) {
+		return
+	}
+
+	// the contents of another kind are not compressed:
+	other := newStructuredContentsForTests(1, 0, 1)
+	if !insertThenCommitForTests(t, app, name, other) {
+		return
+	}
+
+	list := append(append(append(samples, first...), second...), other...)
+	if !retrieveAllForTests(t, app, name, list) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	for _, oneName := range []string{"dictionary.0.1", "dictionary.0.2"} {
+		_, err = reference.Resources().Fetch(oneName)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+
+	for _, oneContent := range append(first, second...) {
+		contentKey, err := reference.ContentKeys().Fetch(oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if contentKey.Codec() != CodecZstdDictionary {
+			t.Errorf("the codec was expected to be %d, %d returned", CodecZstdDictionary, contentKey.Codec())
+			return
+		}
+
+		if contentKey.Content().Length() >= uint(len(oneContent.Data())) {
+			t.Errorf("the content was expected to be compressed to less than %d bytes, %d returned", len(oneContent.Data()), contentKey.Content().Length())
+			return
+		}
+	}
+
+	otherContentKey, err := reference.ContentKeys().Fetch(1, other[0].Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if otherContentKey.Codec() != CodecNone {
+		t.Errorf("the codec was expected to be %d, %d returned", CodecNone, otherContentKey.Codec())
+		return
+	}
+}
+
+func TestTrainDictionary_withoutContents_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, withCompressionsForTests(map[uint]uint{}, nil))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.TrainDictionary(*pContext, 0)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestTrainDictionary_doesNotCommit_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	amountBefore := 0
+	commits := []references.Commit{}
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithOnBeforeCommit(func(context uint, inserts []contents.Content, pendingDeletes []references.ContentKey) error {
+			amountBefore++
+			return nil
+		}).
+			WithOnAfterCommit(func(context uint, commit references.Commit) {
+				commits = append(commits, commit)
+			})
+	})
+
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, newStructuredContentsForTests(0, 0, 50)) {
+		return
+	}
+
+	if !trainDictionaryForTests(t, app, name, 0) {
+		return
+	}
+
+	// the dictionary is stored without a commit, therefore the callbacks are not executed:
+	if amountBefore != 1 {
+		t.Errorf("the before commit callback was expected to be executed %d times, %d returned", 1, amountBefore)
+		return
+	}
+
+	if len(commits) != 1 {
+		t.Errorf("the after commit callback was expected to be executed %d times, %d returned", 1, len(commits))
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(reference.Commits().List()) != 1 {
+		t.Errorf("the database was expected to contain %d commit, %d returned", 1, len(reference.Commits().List()))
+		return
+	}
+
+	if !reference.Commits().Latest().Hash().Compare(commits[0].Hash()) {
+		t.Errorf("the latest commit was expected to remain the commit of the contents")
+		return
+	}
+
+	resource, err := reference.Resources().Fetch(createDictionaryName(0, 1))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(resource.Data()) > dictionaryMaxLength {
+		t.Errorf("the dictionary was expected to contain at most %d bytes, %d returned", dictionaryMaxLength, len(resource.Data()))
+		return
+	}
+}
+
+func trainDictionaryForTests(t *testing.T, app Application, name string, kind uint) bool {
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	err = app.TrainDictionary(*pContext, kind)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func newStructuredContentsForTests(kind uint, from uint, amount uint) []contents.Content {
+	list := []contents.Content{}
+	for i := from; i < from+amount; i++ {
+		data := fmt.Sprintf(`{"identifier":%d,"name":"user_%d","email":"user_%d@example.com","roles":["reader","writer"],"active":true}`, i, i, i)
+		list = append(list, contents.NewContentForTests(kind, []byte(data)))
+	}
+
+	return list
+}
@@ -0,0 +1,148 @@
+package files
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const referenceAdditionalData = "reference"
+const referenceKeyBytesLength = 8
+
+// encryptReference encrypts the reference using the current key, the key identifier is prepended to the returned bytes.
+// The reference is returned as is when there is no key provider
+func (app *application) encryptReference(data []byte) ([]byte, error) {
+	if app.keyProvider == nil {
+		return data, nil
+	}
+
+	keyID, err := app.keyProvider.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	return app.encryptReferenceWithKey(keyID, data)
+}
+
+func (app *application) encryptReferenceWithKey(keyID uint, data []byte) ([]byte, error) {
+	encrypted, err := app.encrypt(keyID, data, []byte(referenceAdditionalData))
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes := make([]byte, referenceKeyBytesLength)
+	binary.LittleEndian.PutUint64(keyBytes, uint64(keyID))
+	return append(keyBytes, encrypted...), nil
+}
+
+// decryptReference decrypts a reference encrypted using encryptReference and returns the identifier of its key, if any.
+// The reference is returned as is when there is no key provider, unless it is encrypted
+func (app *application) decryptReference(data []byte) ([]byte, *uint, error) {
+	if app.keyProvider == nil {
+		if isEncryptedReference(data) {
+			return nil, nil, fmt.Errorf("the reference is encrypted but the application does not contain a key provider: %w", ErrAuthentication)
+		}
+
+		return data, nil, nil
+	}
+
+	if len(data) < referenceKeyBytesLength {
+		return nil, nil, fmt.Errorf("the encrypted reference was expected to contain at least %d bytes, %d provided: %w", referenceKeyBytesLength, len(data), ErrAuthentication)
+	}
+
+	keyID := uint(binary.LittleEndian.Uint64(data[:referenceKeyBytesLength]))
+	decrypted, err := app.decrypt(keyID, data[referenceKeyBytesLength:], []byte(referenceAdditionalData))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decrypted, &keyID, nil
+}
+
+// decryptContent decrypts the data of a content using the key of its content key, if any
+func (app *application) decryptContent(contentKey references.ContentKey, data []byte) ([]byte, error) {
+	if !contentKey.HasKey() {
+		return data, nil
+	}
+
+	return app.decrypt(*contentKey.Key(), data, createContentAdditionalData(contentKey.Kind(), contentKey.Hash()))
+}
+
+// encrypt encrypts the data using AES-GCM, the nonce is prepended to the returned bytes
+func (app *application) encrypt(keyID uint, data []byte, additional []byte) ([]byte, error) {
+	aead, err := app.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, data, additional), nil
+}
+
+// decrypt decrypts data encrypted using encrypt
+func (app *application) decrypt(keyID uint, data []byte, additional []byte) ([]byte, error) {
+	aead, err := app.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("the encrypted data was expected to contain at least %d bytes, %d provided: %w", nonceSize, len(data), ErrAuthentication)
+	}
+
+	decrypted, err := aead.Open(nil, data[:nonceSize], data[nonceSize:], additional)
+	if err != nil {
+		return nil, fmt.Errorf("the encrypted data (key: %d) could not be opened: %w", keyID, ErrAuthentication)
+	}
+
+	return decrypted, nil
+}
+
+func (app *application) aead(keyID uint) (cipher.AEAD, error) {
+	if app.keyProvider == nil {
+		return nil, errors.New("the data is encrypted but the application does not contain a key provider")
+	}
+
+	key, err := app.keyProvider.Fetch(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// isEncryptedReference returns true when the data cannot be a plaintext reference.  A plaintext reference begins with
+// the length of its commits, which contain at least the hash of a commit and fit in the reference.  An encrypted
+// reference begins with the identifier of its key instead, lower than the size of a hash for any realistic key ring
+func isEncryptedReference(data []byte) bool {
+	if len(data) < referenceKeyBytesLength {
+		return false
+	}
+
+	commitsLength := binary.LittleEndian.Uint64(data[:referenceKeyBytesLength])
+	return commitsLength < hash.Size || commitsLength > uint64(len(data)-referenceKeyBytesLength)
+}
+
+// createContentAdditionalData binds an encrypted content to its kind and hash
+func createContentAdditionalData(kind uint, hash hash.Hash) []byte {
+	kindBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(kindBytes, uint64(kind))
+	return append(kindBytes, hash.Bytes()...)
+}
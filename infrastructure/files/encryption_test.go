@@ -0,0 +1,199 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/applications/conformance"
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestApplication_withKeyProvider_Conformance(t *testing.T) {
+	dirPath := "./test_files"
+	conformance.Execute(t, func() (databases.Application, func()) {
+		app := newApplicationForTests(t, dirPath, withKeyForTests([]byte("this is a 32 bytes long key.....")))
+		return app, func() {
+			os.RemoveAll(dirPath)
+		}
+	})
+}
+
+func TestApplication_withKeyProvider_withInvalidKey_returnsAuthenticationError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	data := []byte("this is some personal data")
+	app := newApplicationForTests(t, dirPath, withKeyForTests([]byte("this is a 32 bytes long key.....")))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Insert(*pContext, contents.NewContentForTests(0, data))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	fileBytes, err := os.ReadFile(filepath.Join(dirPath, name))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if bytes.Contains(fileBytes, data) {
+		t.Errorf("the database file was expected to NOT contain the plaintext data")
+		return
+	}
+
+	invalidApp := newApplicationForTests(t, dirPath, withKeyForTests([]byte("this is another 32 bytes key....")))
+	if invalidApp == nil {
+		return
+	}
+
+	_, err = invalidApp.Open(name)
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("the error was expected to be an authentication error, %v returned", err)
+		return
+	}
+}
+
+func TestApplication_withKeyProvider_thenOpenWithoutKeyProvider_returnsAuthenticationError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, withKeyForTests([]byte("this is a 32 bytes long key.....")))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, []contents.Content{contents.NewContentForTests(0, []byte("this is some data"))}) {
+		return
+	}
+
+	plainApp := newApplicationForTests(t, dirPath, nil)
+	if plainApp == nil {
+		return
+	}
+
+	_, err = plainApp.Open(name)
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("the error was expected to be an authentication error, %v returned", err)
+		return
+	}
+
+	// a plaintext reference is not mistaken for an encrypted one:
+	err = plainApp.New("my_plain_name")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, plainApp, "my_plain_name", []contents.Content{contents.NewContentForTests(0, []byte("this is other data"))}) {
+		return
+	}
+
+	pContext, err := plainApp.Open("my_plain_name")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	plainApp.Close(*pContext)
+}
+
+func TestDecrypt_withTamperedData_returnsAuthenticationError(t *testing.T) {
+	keyProvider := NewKeyProvider([]byte("this is a 32 bytes long key....."))
+	positionalApp, err := NewApplication("./test_files", "destination", "backup", 10, nil, nil, nil, nil, keyProvider)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	app := positionalApp.(*application)
+	content := contents.NewContentForTests(0, []byte("this is some data"))
+	additional := createContentAdditionalData(content.Kind(), content.Hash())
+	encrypted, err := app.encrypt(0, content.Data(), additional)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	decrypted, err := app.decrypt(0, encrypted, additional)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(decrypted, content.Data()) {
+		t.Errorf("the decrypted data is invalid")
+		return
+	}
+
+	// the ciphertext is modified:
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = app.decrypt(0, tampered, additional)
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("the error was expected to be an authentication error, %v returned", err)
+		return
+	}
+
+	// the data is bound to the kind of its content:
+	_, err = app.decrypt(0, encrypted, createContentAdditionalData(1, content.Hash()))
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("the error was expected to be an authentication error, %v returned", err)
+		return
+	}
+}
+
+func withKeyForTests(key []byte) func(builder ApplicationBuilder) {
+	return func(builder ApplicationBuilder) {
+		builder.WithKeyProvider(NewKeyProvider(key))
+	}
+}
@@ -0,0 +1,124 @@
+package files
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// the header of the current format contains the magic bytes, the format version, the length of the reference, the
+// checksum of the reference, then the checksum of the previous fields.  The original format (version 0) only contains
+// the length of the reference
+const headerMagic = "SCDB"
+const headerVersionLength = 4
+const headerChecksumLength = 4
+const headerLength = len(headerMagic) + headerVersionLength + expectedReferenceBytesLength + headerChecksumLength*2
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// header represents the decoded header of a database file
+type header struct {
+	version            uint32
+	referenceLength    uint64
+	pReferenceChecksum *uint32
+	length             uint64
+}
+
+// encodeHeader encodes the header of the current format, for the given reference
+func encodeHeader(referenceBytes []byte) []byte {
+	output := []byte(headerMagic)
+	output = binary.LittleEndian.AppendUint32(output, formatVersion)
+	output = binary.LittleEndian.AppendUint64(output, uint64(len(referenceBytes)))
+	output = binary.LittleEndian.AppendUint32(output, computeChecksum(referenceBytes))
+	return binary.LittleEndian.AppendUint32(output, computeChecksum(output))
+}
+
+// readHeader reads the header of the database file, in the current or the original format.  The header of a version
+// more recent than the current one is not decoded beyond its version
+func readHeader(conn StorageFile, name string, size int64) (*header, error) {
+	length := int64(headerLength)
+	if size < length {
+		length = size
+	}
+
+	content := make([]byte, length)
+	_, err := conn.ReadAt(content, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(content, []byte(headerMagic)) {
+		if len(content) < expectedReferenceBytesLength {
+			str := fmt.Sprintf("the database (name: %s) was expected to contain at least %d bytes in order to retrieve the length of its reference, %d found", name, expectedReferenceBytesLength, size)
+			return nil, errors.New(str)
+		}
+
+		return &header{
+			version:         0,
+			referenceLength: binary.LittleEndian.Uint64(content[:expectedReferenceBytesLength]),
+			length:          expectedReferenceBytesLength,
+		}, nil
+	}
+
+	versionBeginsOn := len(headerMagic)
+	lengthBeginsOn := versionBeginsOn + headerVersionLength
+	if len(content) < lengthBeginsOn {
+		str := fmt.Sprintf("the database (name: %s) was expected to contain at least %d bytes in order to retrieve its format version, %d found", name, lengthBeginsOn, size)
+		return nil, errors.New(str)
+	}
+
+	version := binary.LittleEndian.Uint32(content[versionBeginsOn:lengthBeginsOn])
+	if version > formatVersion {
+		return &header{
+			version: version,
+		}, nil
+	}
+
+	if len(content) < headerLength {
+		str := fmt.Sprintf("the database (name: %s) was expected to contain at least %d bytes in order to retrieve its header, %d found", name, headerLength, size)
+		return nil, errors.New(str)
+	}
+
+	checksumBeginsOn := headerLength - headerChecksumLength
+	checksum := binary.LittleEndian.Uint32(content[checksumBeginsOn:headerLength])
+	if checksum != computeChecksum(content[:checksumBeginsOn]) {
+		return nil, fmt.Errorf("the header checksum of the database (name: %s) is invalid: %w", name, ErrCorrupted)
+	}
+
+	lengthEndsOn := lengthBeginsOn + expectedReferenceBytesLength
+	referenceChecksum := binary.LittleEndian.Uint32(content[lengthEndsOn:checksumBeginsOn])
+	return &header{
+		version:            version,
+		referenceLength:    binary.LittleEndian.Uint64(content[lengthBeginsOn:lengthEndsOn]),
+		pReferenceChecksum: &referenceChecksum,
+		length:             uint64(headerLength),
+	}, nil
+}
+
+// verifyVersion returns an error when the header is not in the current format
+func verifyVersion(pHeader *header, name string) error {
+	if pHeader.version < formatVersion {
+		return fmt.Errorf("the database (name: %s) is in the format version %d, version %d expected: %w", name, pHeader.version, formatVersion, ErrOutdatedFormat)
+	}
+
+	if pHeader.version > formatVersion {
+		return fmt.Errorf("the database (name: %s) is in the format version %d, version %d expected: %w", name, pHeader.version, formatVersion, ErrUnsupportedFormat)
+	}
+
+	return nil
+}
+
+// verifyReference returns an error when the reference, as stored, does not match the checksum of the header
+func verifyReference(pHeader *header, name string, referenceBytes []byte) error {
+	if pHeader.pReferenceChecksum == nil {
+		return nil
+	}
+
+	if *pHeader.pReferenceChecksum != computeChecksum(referenceBytes) {
+		return fmt.Errorf("the reference of the database (name: %s), at offset %d, does not match its checksum: %w", name, pHeader.length, ErrCorrupted)
+	}
+
+	return nil
+}
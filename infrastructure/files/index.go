@@ -0,0 +1,344 @@
+package files
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const indexResourceKeyword = "index"
+
+// indexEntry maps an index key to the hash of a content whose data contains the key
+type indexEntry struct {
+	key  []byte
+	hash hash.Hash
+}
+
+// LookupIndex returns the hashes of the committed contents of the kind whose data contains the key in the index.  The
+// entries of the index are sorted by key, therefore the key is searched without decoding the other entries
+func (app *application) LookupIndex(context uint, kind uint, indexName string, key []byte) ([]hash.Hash, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		content, err := app.fetchIndex(pContext, kind, indexName)
+		if err != nil {
+			return nil, err
+		}
+
+		return lookupIndexEntries(content, key)
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot LookupIndex using this context", context)
+	return nil, errors.New(str)
+}
+
+// fetchIndex returns the encoded entries of the index.  The index is built from the committed contents when the
+// database does not contain it yet, or when the context is opened on a tag, since the stored indexes follow the
+// latest commit
+func (app *application) fetchIndex(pContext *context, kind uint, name string) ([]byte, error) {
+	fn, err := app.fetchIndexFn(kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if pContext.pTag == nil && pContext.reference != nil && pContext.reference.HasResources() {
+		resource, err := pContext.reference.Resources().Fetch(createIndexName(kind, name))
+		if err == nil {
+			return resource.Data(), nil
+		}
+	}
+
+	entries, err := app.buildIndex(pContext, kind, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeIndexEntries(entries), nil
+}
+
+// buildIndex builds the entries of the index from the committed contents of the kind
+func (app *application) buildIndex(pContext *context, kind uint, fn IndexFn) ([]indexEntry, error) {
+	entries := []indexEntry{}
+	if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+		return entries, nil
+	}
+
+	// the kind does not contain any content:
+	contentKeys, err := pContext.reference.ContentKeys().ListByKind(kind)
+	if err != nil {
+		return entries, nil
+	}
+
+	for _, oneContentKey := range contentKeys {
+		content, err := app.Retrieve(pContext.identifier, kind, oneContentKey.Hash())
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, createIndexEntries(fn, content.Data(), content.Hash())...)
+	}
+
+	return entries, nil
+}
+
+// updateIndexes returns the resources of the reference in which the indexes of the kinds of the pending inserts and
+// deletes of the context are updated.  The indexes of the other kinds are kept as is, unless the database does not
+// contain them yet.  Returns nil when there is no resource
+func (app *application) updateIndexes(pContext *context) (references.Resources, error) {
+	resourcesList := []references.Resource{}
+	if pContext.reference != nil && pContext.reference.HasResources() {
+		resourcesList = append(resourcesList, pContext.reference.Resources().List()...)
+	}
+
+	touched := map[uint]bool{}
+	for _, oneContent := range pContext.insertList {
+		touched[oneContent.Kind()] = true
+	}
+
+	for _, oneContentKey := range pContext.delList {
+		touched[oneContentKey.Kind()] = true
+	}
+
+	kinds := []uint{}
+	for oneKind := range app.indexes {
+		kinds = append(kinds, oneKind)
+	}
+
+	sort.Slice(kinds, func(i int, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	for _, oneKind := range kinds {
+		names := []string{}
+		for oneName := range app.indexes[oneKind] {
+			names = append(names, oneName)
+		}
+
+		sort.Strings(names)
+		for _, oneName := range names {
+			if !touched[oneKind] && hasResource(resourcesList, createIndexName(oneKind, oneName)) {
+				continue
+			}
+
+			content, err := app.fetchIndex(pContext, oneKind, oneName)
+			if err != nil {
+				return nil, err
+			}
+
+			entries, err := decodeIndexEntries(content)
+			if err != nil {
+				return nil, err
+			}
+
+			updated := []indexEntry{}
+			for _, oneEntry := range entries {
+				keyname := createKeyname(oneKind, oneEntry.hash)
+				if _, ok := pContext.delList[keyname]; ok {
+					continue
+				}
+
+				updated = append(updated, oneEntry)
+			}
+
+			fn := app.indexes[oneKind][oneName]
+			for _, oneContent := range pContext.insertList {
+				if oneContent.Kind() != oneKind {
+					continue
+				}
+
+				updated = append(updated, createIndexEntries(fn, oneContent.Data(), oneContent.Hash())...)
+			}
+
+			resource, err := app.referenceResourceBuilder.Create().
+				WithName(createIndexName(oneKind, oneName)).
+				WithData(encodeIndexEntries(updated)).
+				Now()
+
+			if err != nil {
+				return nil, err
+			}
+
+			resourcesList = replaceResource(resourcesList, resource)
+		}
+	}
+
+	if len(resourcesList) <= 0 {
+		return nil, nil
+	}
+
+	return app.referenceResourcesBuilder.Create().
+		WithList(resourcesList).
+		Now()
+}
+
+func (app *application) fetchIndexFn(kind uint, name string) (IndexFn, error) {
+	if fn, ok := app.indexes[kind][name]; ok {
+		return fn, nil
+	}
+
+	str := fmt.Sprintf("the index (kind: %d, name: %s) is not registered", kind, name)
+	return nil, errors.New(str)
+}
+
+func replaceResource(list []references.Resource, resource references.Resource) []references.Resource {
+	for index, oneResource := range list {
+		if oneResource.Name() == resource.Name() {
+			list[index] = resource
+			return list
+		}
+	}
+
+	return append(list, resource)
+}
+
+func hasResource(list []references.Resource, name string) bool {
+	for _, oneResource := range list {
+		if oneResource.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createIndexEntries(fn IndexFn, data []byte, hash hash.Hash) []indexEntry {
+	entries := []indexEntry{}
+	for _, oneKey := range fn(data) {
+		entries = append(entries, indexEntry{
+			key:  oneKey,
+			hash: hash,
+		})
+	}
+
+	return entries
+}
+
+// encodeIndexEntries encodes the entries sorted by key, then by hash, so that the same index always produces the same
+// bytes.  The amount of entries is followed by the offset of every entry, so that an entry is decoded by its index
+func encodeIndexEntries(entries []indexEntry) []byte {
+	sort.Slice(entries, func(i int, j int) bool {
+		if comparison := bytes.Compare(entries[i].key, entries[j].key); comparison != 0 {
+			return comparison < 0
+		}
+
+		return bytes.Compare(entries[i].hash, entries[j].hash) < 0
+	})
+
+	offsets := make([]byte, 8+8*len(entries))
+	binary.LittleEndian.PutUint64(offsets, uint64(len(entries)))
+	data := []byte{}
+	for index, oneEntry := range entries {
+		binary.LittleEndian.PutUint64(offsets[8+8*index:], uint64(len(offsets)+len(data)))
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(oneEntry.key)))
+		data = append(data, oneEntry.key...)
+		data = append(data, oneEntry.hash...)
+	}
+
+	return append(offsets, data...)
+}
+
+// decodeIndexEntries decodes every entry of the index
+func decodeIndexEntries(content []byte) ([]indexEntry, error) {
+	amount, err := decodeIndexAmount(content)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []indexEntry{}
+	for i := uint64(0); i < amount; i++ {
+		entry, err := decodeIndexEntry(content, i)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// lookupIndexEntries returns the hashes of the entries of the key, using a binary search on the sorted entries
+func lookupIndexEntries(content []byte, key []byte) ([]hash.Hash, error) {
+	amount, err := decodeIndexAmount(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchErr error
+	beginsOn := sort.Search(int(amount), func(index int) bool {
+		entry, err := decodeIndexEntry(content, uint64(index))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+
+		return bytes.Compare(entry.key, key) >= 0
+	})
+
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	output := []hash.Hash{}
+	for i := uint64(beginsOn); i < amount; i++ {
+		entry, err := decodeIndexEntry(content, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(entry.key, key) {
+			break
+		}
+
+		output = append(output, entry.hash)
+	}
+
+	return output, nil
+}
+
+func decodeIndexAmount(content []byte) (uint64, error) {
+	contentLength := uint64(len(content))
+	if contentLength < 8 {
+		str := fmt.Sprintf("the index was expected to contain at least %d bytes, %d provided", 8, contentLength)
+		return 0, errors.New(str)
+	}
+
+	amount := binary.LittleEndian.Uint64(content[:8])
+	if amount > (contentLength-8)/8 {
+		str := fmt.Sprintf("the index was expected to contain at least %d bytes in order to retrieve the offsets of its %d entries, %d provided", 8+8*amount, amount, contentLength)
+		return 0, errors.New(str)
+	}
+
+	return amount, nil
+}
+
+func decodeIndexEntry(content []byte, index uint64) (indexEntry, error) {
+	contentLength := uint64(len(content))
+	offsetBeginsOn := 8 + 8*index
+	beginsOn := binary.LittleEndian.Uint64(content[offsetBeginsOn : offsetBeginsOn+8])
+	if beginsOn > contentLength || contentLength-beginsOn < 8 {
+		str := fmt.Sprintf("the index was expected to contain at least %d bytes in order to retrieve the key size of the entry (index: %d), %d provided", beginsOn+8, index, contentLength)
+		return indexEntry{}, errors.New(str)
+	}
+
+	keyBeginsOn := beginsOn + 8
+	keyLength := binary.LittleEndian.Uint64(content[beginsOn:keyBeginsOn])
+	if keyLength > contentLength-keyBeginsOn || contentLength-keyBeginsOn-keyLength < hash.Size {
+		str := fmt.Sprintf("the index was expected to contain the key (size: %d) and hash of the entry (index: %d) after the offset %d, %d bytes provided", keyLength, index, keyBeginsOn, contentLength)
+		return indexEntry{}, errors.New(str)
+	}
+
+	keyEndsOn := keyBeginsOn + keyLength
+	return indexEntry{
+		key:  content[keyBeginsOn:keyEndsOn],
+		hash: hash.Hash(content[keyEndsOn : keyEndsOn+hash.Size]),
+	}, nil
+}
+
+func createIndexName(kind uint, name string) string {
+	return fmt.Sprintf("%s%s%d%s%s", indexResourceKeyword, fileNameExtensionDelimiter, kind, fileNameExtensionDelimiter, name)
+}
@@ -0,0 +1,352 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+func TestLookupIndex_insert_thenErase_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, withRoleIndexForTests)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("role:reader this is the first data"))
+	second := contents.NewContentForTests(0, []byte("role:writer this is the second data"))
+	third := contents.NewContentForTests(0, []byte("role:reader this is the third data"))
+	other := contents.NewContentForTests(1, []byte("role:reader this is another kind"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first, second, other}) {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, []contents.Content{third}) {
+		return
+	}
+
+	if !lookupIndexForTests(t, app, name, []byte("reader"), []contents.Content{first, third}) {
+		return
+	}
+
+	if !lookupIndexForTests(t, app, name, []byte("writer"), []contents.Content{second}) {
+		return
+	}
+
+	if !lookupIndexForTests(t, app, name, []byte("admin"), []contents.Content{}) {
+		return
+	}
+
+	// erase a content, the index no longer contains it:
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the index is persisted in the database:
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = reference.Resources().Fetch(createIndexName(0, "role"))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the index is not registered on the other kind:
+	_, err = app.LookupIndex(*pContext, 1, "role", []byte("reader"))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	app.Close(*pContext)
+	lookupIndexForTests(t, app, name, []byte("reader"), []contents.Content{third})
+}
+
+func TestLookupIndex_registeredOnExistingContents_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("role:reader this is the first data"))
+	second := contents.NewContentForTests(0, []byte("role:reader this is the second data"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first}) {
+		return
+	}
+
+	// register the index once contents exist, it is built from them then maintained on the next commit:
+	indexedApp := newApplicationForTests(t, dirPath, withRoleIndexForTests)
+	if indexedApp == nil {
+		return
+	}
+
+	if !lookupIndexForTests(t, indexedApp, name, []byte("reader"), []contents.Content{first}) {
+		return
+	}
+
+	if !insertThenCommitForTests(t, indexedApp, name, []contents.Content{second}) {
+		return
+	}
+
+	lookupIndexForTests(t, indexedApp, name, []byte("reader"), []contents.Content{first, second})
+}
+
+func TestIndexEntries_lookup_Success(t *testing.T) {
+	entries := []indexEntry{}
+	expected := map[string][]hash.Hash{}
+	for i := 0; i < 50; i++ {
+		pHash, err := hash.NewAdapter().FromBytes([]byte(fmt.Sprintf("this is the data %d", i)))
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		key := fmt.Sprintf("key_%d", i%7)
+		entries = append(entries, indexEntry{
+			key:  []byte(key),
+			hash: *pHash,
+		})
+
+		expected[key] = append(expected[key], *pHash)
+	}
+
+	content := encodeIndexEntries(entries)
+	for oneKey, oneExpected := range expected {
+		hashes, err := lookupIndexEntries(content, []byte(oneKey))
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if len(hashes) != len(oneExpected) {
+			t.Errorf("the key (%s) was expected to return %d hashes, %d returned", oneKey, len(oneExpected), len(hashes))
+			return
+		}
+
+		for _, oneHash := range oneExpected {
+			if !containsHashForTests(hashes, oneHash) {
+				t.Errorf("the key (%s) was expected to return the hash (%s)", oneKey, oneHash.String())
+				return
+			}
+		}
+	}
+
+	for _, oneKey := range []string{"", "key_", "key_7", "zzz"} {
+		hashes, err := lookupIndexEntries(content, []byte(oneKey))
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if len(hashes) != 0 {
+			t.Errorf("the absent key (%s) was expected to return no hash, %d returned", oneKey, len(hashes))
+			return
+		}
+	}
+
+	retEntries, err := decodeIndexEntries(content)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(retEntries) != len(entries) {
+		t.Errorf("the decoded index was expected to contain %d entries, %d returned", len(entries), len(retEntries))
+		return
+	}
+
+	_, err = lookupIndexEntries(content[:len(content)-1], []byte("key_6"))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	_, err = decodeIndexEntries(content[:16])
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestUpdateIndexes_keepsUntouchedKinds_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, withRoleIndexForTests)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	first := contents.NewContentForTests(0, []byte("role:reader this is the first data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first}) {
+		return
+	}
+
+	filesApp := app.(*application)
+	pAppContext := filesApp.contexts[*pContext]
+	indexName := createIndexName(0, "role")
+	stored, err := pAppContext.reference.Resources().Fetch(indexName)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the commit of another kind keeps the stored index:
+	err = app.Insert(*pContext, contents.NewContentForTests(1, []byte("role:writer this is another kind")))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	resources, err := filesApp.updateIndexes(pAppContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	kept, err := resources.Fetch(indexName)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if kept != stored {
+		t.Errorf("the index of the untouched kind was expected to be kept as is")
+		return
+	}
+
+	// the commit of the kind rewrites its index:
+	err = app.Insert(*pContext, contents.NewContentForTests(0, []byte("role:writer this is the second data")))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	resources, err = filesApp.updateIndexes(pAppContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	rewritten, err := resources.Fetch(indexName)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if rewritten == stored {
+		t.Errorf("the index of the touched kind was expected to be rewritten")
+		return
+	}
+}
+
+func lookupIndexForTests(t *testing.T, app Application, name string, key []byte, expected []contents.Content) bool {
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	hashes, err := app.LookupIndex(*pContext, 0, "role", key)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	if len(hashes) != len(expected) {
+		t.Errorf("%d hashes were expected, %d returned", len(expected), len(hashes))
+		return false
+	}
+
+	for _, oneContent := range expected {
+		if !containsHashForTests(hashes, oneContent.Hash()) {
+			t.Errorf("the content (hash: %s) was expected to be returned", oneContent.Hash().String())
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsHashForTests(hashes []hash.Hash, expected hash.Hash) bool {
+	for _, oneHash := range hashes {
+		if oneHash.Compare(expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withRoleIndexForTests(builder ApplicationBuilder) {
+	builder.WithIndex(0, "role", func(data []byte) [][]byte {
+		prefix := []byte("role:")
+		if !bytes.HasPrefix(data, prefix) {
+			return [][]byte{}
+		}
+
+		return [][]byte{
+			bytes.Fields(data[len(prefix):])[0],
+		}
+	})
+}
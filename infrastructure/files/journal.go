@@ -0,0 +1,200 @@
+package files
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// the journal contains the header and reference that replace the ones of the database, followed by their checksum
+const journalExtension = "journal"
+const journalChecksumLength = 4
+
+// amend appends the data to the data region of the database of the context, then replaces its reference in place.
+// The data already written is never moved nor overwritten, therefore the contexts opened on the database keep reading
+// it.  The new header and reference are first written in a journal, so that an interrupted replacement is completed
+// when the database is opened again.  The database is rewritten when the reference does not keep its length
+func (app *application) amend(pContext *context, referenceBytes []byte, data []byte) error {
+	header := append(encodeHeader(referenceBytes), referenceBytes...)
+	if pContext.pHeader == nil || uint64(len(header)) != pContext.pHeader.length+pContext.pHeader.referenceLength {
+		return app.rewrite(pContext, referenceBytes, data)
+	}
+
+	err := app.verifyCurrent(pContext)
+	if err != nil {
+		return err
+	}
+
+	// append the data, then make it durable before the reference points to it:
+	size, err := pContext.conn.Size()
+	if err != nil {
+		return err
+	}
+
+	_, err = pContext.conn.WriteAt(data, size)
+	if err != nil {
+		return err
+	}
+
+	err = pContext.conn.Sync()
+	if err != nil {
+		return err
+	}
+
+	journalPath := app.journalPath(pContext.name)
+	err = app.writeJournal(journalPath, header)
+	if err != nil {
+		app.storage.Remove(journalPath)
+		return err
+	}
+
+	err = app.applyJournal(pContext.conn, header)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.Remove(journalPath)
+	if err != nil {
+		return err
+	}
+
+	return app.readReference(pContext)
+}
+
+func (app *application) writeJournal(path string, header []byte) error {
+	err := app.storage.Create(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := app.storage.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+	content := binary.LittleEndian.AppendUint32(append([]byte{}, header...), computeChecksum(header))
+	_, err = file.WriteAt(content, 0)
+	if err != nil {
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	// the journal must remain once the database is modified:
+	return app.storage.SyncDir(filepath.Dir(path))
+}
+
+func (app *application) applyJournal(conn StorageFile, header []byte) error {
+	_, err := conn.WriteAt(header, 0)
+	if err != nil {
+		return err
+	}
+
+	return conn.Sync()
+}
+
+// replayJournal completes the replacement of the reference of the database when it was interrupted.  A journal that
+// does not match its checksum was interrupted while written, before the database was modified, and is discarded.
+// The journal of a database locked by another writer is left to that writer
+func (app *application) replayJournal(name string) error {
+	journalPath := app.journalPath(name)
+	exists, err := app.storage.Exists(journalPath)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	lock, err := app.storage.Lock(app.lockPath(name))
+	if err != nil {
+		return nil
+	}
+
+	defer lock.Unlock()
+	header, err := app.readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+
+	if header != nil {
+		conn, err := app.storage.Open(filepath.Join(app.dirPath, name))
+		if err != nil {
+			return err
+		}
+
+		err = app.applyJournal(conn, header)
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+
+		if closeErr != nil {
+			return closeErr
+		}
+	}
+
+	return app.storage.Remove(journalPath)
+}
+
+// readJournal returns the header and reference of the journal, or nil when the journal does not match its checksum
+func (app *application) readJournal(path string) ([]byte, error) {
+	file, err := app.storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+	size, err := file.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= journalChecksumLength {
+		return nil, nil
+	}
+
+	content := make([]byte, size)
+	_, err = file.ReadAt(content, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := len(content) - journalChecksumLength
+	header := content[:delimiter]
+	if binary.LittleEndian.Uint32(content[delimiter:]) != computeChecksum(header) {
+		return nil, nil
+	}
+
+	if len(header) < headerLength {
+		str := fmt.Sprintf("the journal (path: %s) was expected to contain at least %d bytes, %d found", path, headerLength, len(header))
+		return nil, errors.New(str)
+	}
+
+	return header, nil
+}
+
+func (app *application) removeJournal(name string) error {
+	journalPath := app.journalPath(name)
+	exists, err := app.storage.Exists(journalPath)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	return app.storage.Remove(journalPath)
+}
+
+func (app *application) journalPath(name string) string {
+	journalName := fmt.Sprintf("%s%s%s", name, fileNameExtensionDelimiter, journalExtension)
+	return filepath.Join(app.dirPath, journalName)
+}
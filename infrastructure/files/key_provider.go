@@ -0,0 +1,43 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+)
+
+type keyProvider struct {
+	current uint
+	keys    map[uint][]byte
+}
+
+func createKeyProvider(
+	current uint,
+	keys map[uint][]byte,
+) KeyProvider {
+	out := keyProvider{
+		current: current,
+		keys:    keys,
+	}
+
+	return &out
+}
+
+// Current returns the identifier of the key used to encrypt
+func (obj *keyProvider) Current() (uint, error) {
+	if _, ok := obj.keys[obj.current]; !ok {
+		str := fmt.Sprintf("the current key (id: %d) is not contained in the key provider", obj.current)
+		return 0, errors.New(str)
+	}
+
+	return obj.current, nil
+}
+
+// Fetch fetches a key by identifier
+func (obj *keyProvider) Fetch(id uint) ([]byte, error) {
+	if key, ok := obj.keys[id]; ok {
+		return key, nil
+	}
+
+	str := fmt.Sprintf("the key (id: %d) is not contained in the key provider", id)
+	return nil, errors.New(str)
+}
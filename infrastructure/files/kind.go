@@ -0,0 +1,101 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// RegisterKind registers the name, description and schema of a kind number in the database.  Registering
+// a number that is already registered replaces its registration
+func (app *application) RegisterKind(context uint, kind references.Kind) error {
+	if pContext, ok := app.contexts[context]; ok {
+		err := app.verifyWritable(pContext)
+		if err != nil {
+			return err
+		}
+
+		if pContext.reference == nil {
+			str := fmt.Sprintf("the database (name: %s) does not contain any commit yet and therefore cannot register kinds", pContext.name)
+			return errors.New(str)
+		}
+
+		kindsList := []references.Kind{}
+		isReplaced := false
+		if pContext.reference.HasKinds() {
+			for _, oneKind := range pContext.reference.Kinds().List() {
+				if oneKind.Number() == kind.Number() {
+					kindsList = append(kindsList, kind)
+					isReplaced = true
+					continue
+				}
+
+				kindsList = append(kindsList, oneKind)
+			}
+		}
+
+		if !isReplaced {
+			kindsList = append(kindsList, kind)
+		}
+
+		kinds, err := app.referenceKindsBuilder.Create().
+			WithList(kindsList).
+			Now()
+
+		if err != nil {
+			return err
+		}
+
+		builder := app.referenceBuilder.Create().
+			WithCommits(pContext.reference.Commits()).
+			WithKinds(kinds)
+
+		if pContext.reference.HasContentKeys() {
+			builder.WithContentKeys(pContext.reference.ContentKeys())
+		}
+
+		if pContext.reference.HasResources() {
+			builder.WithResources(pContext.reference.Resources())
+		}
+
+		if pContext.reference.HasTags() {
+			builder.WithTags(pContext.reference.Tags())
+		}
+
+		reference, err := builder.Now()
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err := app.referenceAdapter.ToContent(reference)
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err = app.encryptReference(referenceBytes)
+		if err != nil {
+			return err
+		}
+
+		return app.rewrite(pContext, referenceBytes, []byte{})
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot RegisterKind using this context", context)
+	return errors.New(str)
+}
+
+// ListKinds returns the kinds registered in the database, in registration order
+func (app *application) ListKinds(context uint) ([]references.Kind, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if pContext.reference == nil || !pContext.reference.HasKinds() {
+			return []references.Kind{}, nil
+		}
+
+		return pContext.reference.Kinds().List(), nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot ListKinds using this context", context)
+	return nil, errors.New(str)
+}
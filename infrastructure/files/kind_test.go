@@ -0,0 +1,111 @@
+package files
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+func TestRegisterKind_thenListKinds_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	first := references.NewKindForTests(0, true)
+
+	// kinds cannot be registered before the first commit:
+	err := app.RegisterKind(*pContext, first)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{contents.NewContentForTests(0, []byte("this is some data"))}) {
+		return
+	}
+
+	second := references.NewKindForTests(1, false)
+	for _, oneKind := range []references.Kind{first, second} {
+		err = app.RegisterKind(*pContext, oneKind)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+
+	// registering a number again replaces its registration:
+	replaced, err := references.NewKindBuilder().Create().
+		WithNumber(second.Number()).
+		WithName("renamed").
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.RegisterKind(*pContext, replaced)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// a name cannot be registered on two numbers:
+	duplicated, err := references.NewKindBuilder().Create().
+		WithNumber(2).
+		WithName(first.Name()).
+		Now()
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.RegisterKind(*pContext, duplicated)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	kinds, err := app.ListKinds(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(kinds, []references.Kind{first, replaced}) {
+		t.Errorf("the returned kinds are invalid")
+		return
+	}
+
+	// the kinds are kept by the following commits:
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{contents.NewContentForTests(1, []byte("this is some data"))}) {
+		return
+	}
+
+	kinds, err = app.ListKinds(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(kinds) != 2 {
+		t.Errorf("%d kinds were expected, %d returned", 2, len(kinds))
+		return
+	}
+}
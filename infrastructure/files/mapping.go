@@ -0,0 +1,82 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+)
+
+// mappableFile represents a storage file that can be mapped in memory
+type mappableFile interface {
+	Map(size int64) ([]byte, error)
+}
+
+// OpenMapped opens a read-only context on the latest commit of the database, whose reads are served as slices of a
+// memory mapping of its file
+func (app *application) OpenMapped(name string) (*uint, error) {
+	return app.open(name, nil, true)
+}
+
+// remap maps the file of the connection of the context, replacing the previous mapping.  The commits replace the
+// database file, therefore the file is mapped again every time the connection is.  The previous mapping is kept until
+// the context is closed, since the data returned by the context may still refer to it
+func (app *application) remap(pContext *context) error {
+	if pContext.mapping != nil {
+		pContext.superseded = append(pContext.superseded, pContext.mapping)
+		pContext.mapping = nil
+	}
+
+	file, ok := pContext.conn.(mappableFile)
+	if !ok {
+		str := fmt.Sprintf("the storage of the database (name: %s) does not support memory mapping", pContext.name)
+		return errors.New(str)
+	}
+
+	size, err := pContext.conn.Size()
+	if err != nil {
+		return err
+	}
+
+	// an empty file cannot be mapped:
+	if size <= 0 {
+		return nil
+	}
+
+	mapping, err := file.Map(size)
+	if err != nil {
+		return err
+	}
+
+	pContext.mapping = mapping
+	return nil
+}
+
+// unmap unmaps the file of the context, if mapped, along with its superseded mappings.  The slices of the mappings
+// must not be used afterwards
+func (app *application) unmap(pContext *context) error {
+	if pContext.mapping != nil {
+		pContext.superseded = append(pContext.superseded, pContext.mapping)
+		pContext.mapping = nil
+	}
+
+	for len(pContext.superseded) > 0 {
+		err := unmapFile(pContext.superseded[0])
+		if err != nil {
+			return err
+		}
+
+		pContext.superseded = pContext.superseded[1:]
+	}
+
+	return nil
+}
+
+// readMapping returns the slice of the mapping of the context, at offset
+func readMapping(pContext *context, offset uint, length uint) ([]byte, error) {
+	endsOn := offset + length
+	if endsOn < offset || endsOn > uint(len(pContext.mapping)) {
+		str := fmt.Sprintf("the Read operation was expected to read %d bytes at offset %d, the mapping of the database (name: %s) contains %d bytes", length, offset, pContext.name, len(pContext.mapping))
+		return nil, errors.New(str)
+	}
+
+	return pContext.mapping[offset:endsOn:endsOn], nil
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package files
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// mapFile returns an error since the platform does not support memory mapping
+func mapFile(pFile *os.File, size int64) ([]byte, error) {
+	str := fmt.Sprintf("the platform (%s) does not support memory mapping", runtime.GOOS)
+	return nil, errors.New(str)
+}
+
+// unmapFile returns an error since the platform does not support memory mapping
+func unmapFile(mapping []byte) error {
+	str := fmt.Sprintf("the platform (%s) does not support memory mapping", runtime.GOOS)
+	return errors.New(str)
+}
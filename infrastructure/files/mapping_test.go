@@ -0,0 +1,376 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+// unmappableStorageForTests opens files that only provide the StorageFile methods
+type unmappableStorageForTests struct {
+	Storage
+}
+
+type unmappableFileForTests struct {
+	StorageFile
+}
+
+// Open opens the file at path
+func (obj *unmappableStorageForTests) Open(path string) (StorageFile, error) {
+	file, err := obj.Storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unmappableFileForTests{file}, nil
+}
+
+func TestOpenMapped_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// an empty database can be mapped:
+	pContext, err := app.OpenMapped(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(1, []byte("this is the second data"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+
+	pOpened, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	expected, err := app.Read(*pOpened, 0, 64)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	app.Close(*pOpened)
+	pContext, err = app.OpenMapped(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	data, err := app.Read(*pContext, 0, 64)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(expected, data) {
+		t.Errorf("the mapped data was expected to be identical to the read data")
+		return
+	}
+
+	for _, oneContent := range []contents.Content{first, second} {
+		retContent, err := app.Retrieve(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !bytes.Equal(retContent.Data(), oneContent.Data()) {
+			t.Errorf("the retrieved content (kind: %d) is invalid", oneContent.Kind())
+			return
+		}
+	}
+
+	// reading past the mapping fails:
+	_, err = app.Read(*pContext, 0, 1024*1024)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the mapped context is read-only:
+	err = app.Insert(*pContext, contents.NewContentForTests(0, []byte("this is the third data")))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the commits of another process are mapped once refreshed:
+	third := contents.NewContentForTests(0, []byte("this is the third data"))
+	other := newApplicationForTests(t, dirPath, nil)
+	if other == nil {
+		return
+	}
+
+	if !insertThenCommitForTests(t, other, name, []contents.Content{third}) {
+		return
+	}
+
+	err = app.Refresh(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retContent, err := app.Retrieve(*pContext, third.Kind(), third.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(retContent.Data(), third.Data()) {
+		t.Errorf("the retrieved content is invalid")
+		return
+	}
+}
+
+func TestOpenMapped_thenWatchedCommit_keepsRetrievedData_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithReadCache(1024)
+	})
+
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	if !insertThenCommitForTests(t, app, name, []contents.Content{first}) {
+		return
+	}
+
+	pContext, err := app.OpenMapped(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	retContent, err := app.Retrieve(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	events, cancelFn, err := app.Watch(name, 10*time.Millisecond)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer cancelFn()
+	other := newApplicationForTests(t, dirPath, nil)
+	if other == nil {
+		return
+	}
+
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	if !insertThenCommitForTests(t, other, name, []contents.Content{second}) {
+		return
+	}
+
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Errorf("the commit was expected to be detected by the watcher")
+		return
+	}
+
+	// the watched commit refreshes the context on its next read, which maps the file again:
+	has, err := app.Has(*pContext, second.Kind(), second.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !has {
+		t.Errorf("the refreshed context was expected to contain the watched commit")
+		return
+	}
+
+	if !bytes.Equal(retContent.Data(), first.Data()) {
+		t.Errorf("the data retrieved before the refresh was expected to be kept")
+		return
+	}
+
+	// the cached data outlives the mapping of the closed context:
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, []contents.Content{first, second}) {
+		return
+	}
+}
+
+func TestOpenMapped_withoutMappableStorage_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	storage := &unmappableStorageForTests{NewOSStorage()}
+	app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+		builder.WithStorage(storage)
+	})
+
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.OpenMapped(name)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the failed open does not leave any context behind:
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	app.Close(*pContext)
+}
+
+func BenchmarkRead_withReadAt(b *testing.B) {
+	benchmarkReadForTests(b, false)
+}
+
+func BenchmarkRead_withMapping(b *testing.B) {
+	benchmarkReadForTests(b, true)
+}
+
+func BenchmarkRetrieve_withReadAt(b *testing.B) {
+	benchmarkRetrieveForTests(b, false)
+}
+
+func BenchmarkRetrieve_withMapping(b *testing.B) {
+	benchmarkRetrieveForTests(b, true)
+}
+
+func benchmarkReadForTests(b *testing.B, isMapped bool) {
+	app, pContext, list, cleanupFn := openBenchmarkForTests(b, isMapped)
+	defer cleanupFn()
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+	}
+
+	dataOffset := app.(*application).contexts[*pContext].dataOffset
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pointer, err := reference.ContentKeys().Fetch(list[i%len(list)].Kind(), list[i%len(list)].Hash())
+		if err != nil {
+			b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+		}
+
+		_, err = app.Read(*pContext, dataOffset+pointer.Content().From(), pointer.Content().Length())
+		if err != nil {
+			b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+		}
+	}
+}
+
+func benchmarkRetrieveForTests(b *testing.B, isMapped bool) {
+	app, pContext, list, cleanupFn := openBenchmarkForTests(b, isMapped)
+	defer cleanupFn()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		oneContent := list[i%len(list)]
+		_, err := app.Retrieve(*pContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+		}
+	}
+}
+
+func openBenchmarkForTests(b *testing.B, isMapped bool) (Application, *uint, []contents.Content, func()) {
+	dirPath := "./test_files"
+	name := "my_name"
+	app, err := NewApplication(dirPath, "destination", "backup", 1024*1024, nil, nil, nil, nil, nil)
+	if err != nil {
+		b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+	}
+
+	err = app.New(name)
+	if err != nil {
+		b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+	}
+
+	list := []contents.Content{}
+	for i := 0; i < 256; i++ {
+		data := bytes.Repeat([]byte(fmt.Sprintf("this is the data %d, ", i)), 256)
+		list = append(list, contents.NewContentForTests(0, data))
+	}
+
+	if !insertThenCommitForTests(b, app, name, list) {
+		b.FailNow()
+	}
+
+	openFn := app.Open
+	if isMapped {
+		openFn = app.OpenMapped
+	}
+
+	pContext, err := openFn(name)
+	if err != nil {
+		b.Fatalf("the error was expected to be nil, error returned: %s", err.Error())
+	}
+
+	return app, pContext, list, func() {
+		app.Close(*pContext)
+		os.RemoveAll(dirPath)
+	}
+}
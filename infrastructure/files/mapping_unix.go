@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package files
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the given amount of bytes of the file, read-only
+func mapFile(pFile *os.File, size int64) ([]byte, error) {
+	return syscall.Mmap(int(pFile.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapFile unmaps a mapping returned by mapFile
+func unmapFile(mapping []byte) error {
+	return syscall.Munmap(mapping)
+}
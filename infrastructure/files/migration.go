@@ -0,0 +1,255 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// Migrate upgrades, in place, the database from a previous format version to the current one
+func (app *application) Migrate(name string) error {
+	for _, oneContext := range app.contexts {
+		if oneContext.name == name {
+			str := fmt.Sprintf("there is an open context for the database (name: %s) and therefore it cannot be migrated", name)
+			return errors.New(str)
+		}
+	}
+
+	exists, err := app.Exists(name)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		str := fmt.Sprintf("the database (name: %s) does not exists and therefore cannot be migrated", name)
+		return errors.New(str)
+	}
+
+	sourcePath := filepath.Join(app.dirPath, name)
+	lock, err := app.storage.Lock(app.lockPath(name))
+	if err != nil {
+		return err
+	}
+
+	defer lock.Unlock()
+	conn, err := app.storage.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	destination := app.destinationName(name)
+	isMigrated, err := app.writeMigration(conn, name, destination)
+	closeErr := conn.Close()
+	if err != nil || !isMigrated {
+		app.storage.Remove(filepath.Join(app.dirPath, destination))
+		return err
+	}
+
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return app.swap(sourcePath, app.backupPath(name), filepath.Join(app.dirPath, destination))
+}
+
+// writeMigration writes the database in the current format to the destination.  The reference is converted to
+// contain the checksum of every content, while the data keeps its encoding.  Returns false when the database is
+// already in the current format, or empty
+func (app *application) writeMigration(conn StorageFile, name string, destination string) (bool, error) {
+	size, err := conn.Size()
+	if err != nil {
+		return false, err
+	}
+
+	if size <= 0 {
+		return false, nil
+	}
+
+	pHeader, err := readHeader(conn, name, size)
+	if err != nil {
+		return false, err
+	}
+
+	if pHeader.version == formatVersion {
+		return false, nil
+	}
+
+	if pHeader.version > formatVersion {
+		return false, verifyVersion(pHeader, name)
+	}
+
+	referenceDelimiter := pHeader.length + pHeader.referenceLength
+	if uint64(size) < referenceDelimiter {
+		str := fmt.Sprintf("the database (name: %s) was expected to contain at least %d bytes, %d found", name, referenceDelimiter, size)
+		return false, errors.New(str)
+	}
+
+	referenceBytes := make([]byte, pHeader.referenceLength)
+	_, err = conn.ReadAt(referenceBytes, int64(pHeader.length))
+	if err != nil {
+		return false, err
+	}
+
+	referenceBytes, err = app.migrateReference(conn, referenceBytes, uint(referenceDelimiter), uint(size))
+	if err != nil {
+		return false, err
+	}
+
+	destinationPath := filepath.Join(app.dirPath, destination)
+	err = app.storage.Create(destinationPath)
+	if err != nil {
+		return false, err
+	}
+
+	file, err := app.storage.Open(destinationPath)
+	if err != nil {
+		return false, err
+	}
+
+	defer file.Close()
+	header := append(encodeHeader(referenceBytes), referenceBytes...)
+	_, err = file.WriteAt(header, 0)
+	if err != nil {
+		return false, err
+	}
+
+	// copy the data, chunk by chunk:
+	offset := int64(len(header)) - int64(referenceDelimiter)
+	index := int64(referenceDelimiter)
+	for index < size {
+		length := int64(app.readChunkSize)
+		if index+length > size {
+			length = size - index
+		}
+
+		chunk := make([]byte, length)
+		_, err = conn.ReadAt(chunk, index)
+		if err != nil {
+			return false, err
+		}
+
+		_, err = file.WriteAt(chunk, index+offset)
+		if err != nil {
+			return false, err
+		}
+
+		index += length
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// migrateReference converts the reference of the original format (version 0), which predates the checksum of the
+// pointers, the codec and key of the content keys, the tags, the resources and the encryption.  The checksums are
+// computed on the data, as stored after the data offset
+func (app *application) migrateReference(conn StorageFile, referenceBytes []byte, dataOffset uint, size uint) ([]byte, error) {
+	reference, err := app.referenceOriginalAdapter.ToReference(referenceBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	checksumFn := func(contentKey references.ContentKey) (references.ContentKey, error) {
+		return app.checksumContentKey(conn, contentKey, dataOffset, size)
+	}
+
+	builder := app.referenceBuilder.Create().WithCommits(reference.Commits())
+	contentKeysList := []references.ContentKey{}
+	if reference.HasContentKeys() {
+		list, err := app.rotateContentKeys(reference.ContentKeys().List(), checksumFn)
+		if err != nil {
+			return nil, err
+		}
+
+		contentKeysList = list
+
+		contentKeys, err := app.referenceContentKeysBuilder.Create().
+			WithList(contentKeysList).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		builder.WithContentKeys(contentKeys)
+	}
+
+	// the bloom filters are built from the content keys:
+	resourcesList, err := app.rebuildBloomFilters(contentKeysList, []references.Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourcesList) > 0 {
+		resources, err := app.referenceResourcesBuilder.Create().
+			WithList(resourcesList).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		builder.WithResources(resources)
+	}
+
+	if reference.HasKinds() {
+		builder.WithKinds(reference.Kinds())
+	}
+
+	migrated, err := builder.Now()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := app.referenceAdapter.ToContent(migrated)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.encryptReference(output)
+}
+
+// checksumContentKey returns the content key with the checksum of its content, as stored after the data offset
+func (app *application) checksumContentKey(conn StorageFile, contentKey references.ContentKey, dataOffset uint, size uint) (references.ContentKey, error) {
+	pointer := contentKey.Content()
+	endsOn := dataOffset + pointer.From() + pointer.Length()
+	if endsOn > size {
+		str := fmt.Sprintf("the content (kind: %d, hash: %s) was expected to end at offset %d, the database contains %d bytes", contentKey.Kind(), contentKey.Hash().String(), endsOn, size)
+		return nil, errors.New(str)
+	}
+
+	data := make([]byte, pointer.Length())
+	_, err := conn.ReadAt(data, int64(dataOffset+pointer.From()))
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPointer, err := app.referencePointerBuilder.Create().
+		From(pointer.From()).
+		WithLength(pointer.Length()).
+		WithChecksum(computeChecksum(data)).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	builder := app.referenceContentKeyBuilder.Create().
+		WithHash(contentKey.Hash()).
+		WithKind(contentKey.Kind()).
+		WithContent(updatedPointer).
+		WithCommit(contentKey.Commit()).
+		WithCodec(contentKey.Codec())
+
+	if contentKey.HasKey() {
+		builder.WithKey(*contentKey.Key())
+	}
+
+	return builder.Now()
+}
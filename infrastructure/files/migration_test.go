@@ -0,0 +1,213 @@
+package files
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestMigrate_fromOriginalFormat_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the empty databases do not contain any header:
+	err = app.Migrate(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the fixture was written in the original format, which only contains the length of the reference and two commits:
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(1, []byte("this is the second data"))
+	third := contents.NewContentForTests(0, []byte("this is the third data"))
+	fourth := contents.NewContentForTests(1, []byte("this is the fourth data"))
+	path := filepath.Join(dirPath, name)
+	legacy, err := os.ReadFile(filepath.Join("testdata", "legacy_v0"))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = os.WriteFile(path, legacy, filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Open(name)
+	if !errors.Is(err, ErrOutdatedFormat) {
+		t.Errorf("the error was expected to be ErrOutdatedFormat, %v returned", err)
+		return
+	}
+
+	err = app.Migrate(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	version := binary.LittleEndian.Uint32(migrated[len(headerMagic):])
+	if version != formatVersion {
+		t.Errorf("the migrated database was expected to be in the version %d, %d returned", formatVersion, version)
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, []contents.Content{first, second, fourth}) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(reference.Commits().List()) != 2 {
+		t.Errorf("the migrated database was expected to contain %d commits, %d returned", 2, len(reference.Commits().List()))
+		return
+	}
+
+	_, err = app.Retrieve(*pContext, third.Kind(), third.Hash())
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the bloom filters are built by the migration:
+	for _, oneKind := range []uint{first.Kind(), second.Kind()} {
+		pFilter, err := app.(*application).fetchBloomFilter(app.(*application).contexts[*pContext], oneKind)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if pFilter == nil {
+			t.Errorf("the migrated database was expected to contain the bloom filter of the kind %d", oneKind)
+			return
+		}
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// a migrated database is left untouched:
+	err = app.Migrate(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	untouched, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(migrated, untouched) {
+		t.Errorf("the migrated database was expected to be left untouched")
+		return
+	}
+}
+
+func TestOpen_withInvalidHeader_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, []contents.Content{contents.NewContentForTests(0, []byte("this is some data"))}) {
+		return
+	}
+
+	path := filepath.Join(dirPath, name)
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// a more recent format version is not supported:
+	recent := binary.LittleEndian.AppendUint32([]byte(headerMagic), formatVersion+1)
+	recent = append(recent, current[8:20]...)
+	recent = binary.LittleEndian.AppendUint32(recent, crc32.Checksum(recent, crc32cTable))
+	err = os.WriteFile(path, append(recent, current[headerLength:]...), filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Open(name)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("the error was expected to be ErrUnsupportedFormat, %v returned", err)
+		return
+	}
+
+	err = app.Migrate(name)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("the error was expected to be ErrUnsupportedFormat, %v returned", err)
+		return
+	}
+
+	// a header whose checksum does not match is rejected:
+	corrupted := append([]byte{}, current...)
+	corrupted[8]++
+	err = os.WriteFile(path, corrupted, filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = app.Open(name)
+	if !errors.Is(err, ErrCorrupted) {
+		t.Errorf("the error was expected to be ErrCorrupted, %v returned", err)
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package files
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE
+
+type inotifyNotifier struct {
+	file          *os.File
+	name          string
+	notifications chan struct{}
+}
+
+// createNotifier creates an inotify notifier on the directory of the database, since the database file is replaced,
+// rather than modified, on every commit
+func createNotifier(dirPath string, name string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dirPath, name)
+	_, err = syscall.InotifyAddWatch(fd, filepath.Dir(path), inotifyMask)
+	if err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+
+	out := inotifyNotifier{
+		file:          os.NewFile(uintptr(fd), "inotify"),
+		name:          filepath.Base(path),
+		notifications: make(chan struct{}, 1),
+	}
+
+	go out.read()
+	return &out, nil
+}
+
+// Notifications returns the notifications of the changes of the database file
+func (obj *inotifyNotifier) Notifications() <-chan struct{} {
+	return obj.notifications
+}
+
+// Close stops watching the database file
+func (obj *inotifyNotifier) Close() error {
+	return obj.file.Close()
+}
+
+// read reads the inotify events until the notifier is closed, notifying the ones on the database file
+func (obj *inotifyNotifier) read() {
+	buffer := make([]byte, (syscall.SizeofInotifyEvent+syscall.NAME_MAX+1)*16)
+	for {
+		amount, err := obj.file.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		offset := 0
+		for offset+syscall.SizeofInotifyEvent <= amount {
+			nameLength := int(binary.NativeEndian.Uint32(buffer[offset+12 : offset+16]))
+			nameBeginsOn := offset + syscall.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buffer[nameBeginsOn:nameBeginsOn+nameLength], "\x00"))
+			offset = nameBeginsOn + nameLength
+			if name != obj.name {
+				continue
+			}
+
+			select {
+			case obj.notifications <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
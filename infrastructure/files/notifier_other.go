@@ -0,0 +1,15 @@
+//go:build !linux
+
+package files
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// createNotifier returns an error since the platform does not provide a notifier, the database file is then polled
+func createNotifier(dirPath string, name string) (notifier, error) {
+	str := fmt.Sprintf("the platform (%s) does not provide a notifier", runtime.GOOS)
+	return nil, errors.New(str)
+}
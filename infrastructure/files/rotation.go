@@ -0,0 +1,274 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// RotateKey re-encrypts the contents and reference of the database encrypted using the old key with the new key
+func (app *application) RotateKey(name string, oldKeyID uint, newKeyID uint) error {
+	if app.keyProvider == nil {
+		return errors.New("the application does not contain a key provider and therefore cannot rotate keys")
+	}
+
+	// make sure the new key can be fetched before touching the database:
+	_, err := app.keyProvider.Fetch(newKeyID)
+	if err != nil {
+		return err
+	}
+
+	pContext, err := app.openContext(name, nil, false)
+	if err != nil {
+		return err
+	}
+
+	defer app.Close(*pContext)
+	err = app.lock(*pContext)
+	if err != nil {
+		return err
+	}
+
+	for {
+		isDone, err := app.rotateKeyBatch(app.contexts[*pContext], oldKeyID, newKeyID)
+		if err != nil {
+			return err
+		}
+
+		if isDone {
+			return nil
+		}
+	}
+}
+
+// rotateKeyBatch re-encrypts at most rotationBatchSize bytes of contents encrypted using the old key, then commits them.
+// The re-encrypted contents are appended to the data region and the reference keeps its length, therefore a batch only
+// writes its contents and the reference.  Returns true when no content or reference remains encrypted using the old key
+func (app *application) rotateKeyBatch(pContext *context, oldKeyID uint, newKeyID uint) (bool, error) {
+	if pContext.reference == nil {
+		return true, nil
+	}
+
+	dataLength, err := app.dataLength(pContext)
+	if err != nil {
+		return false, err
+	}
+
+	contentKeysList := []references.ContentKey{}
+	if pContext.reference.HasContentKeys() {
+		contentKeysList = pContext.reference.ContentKeys().List()
+	}
+
+	// the contents pinned by tags or deleted may share their data with the current contents, therefore the rotated
+	// content keys are tracked by the position of their data:
+	isDone := true
+	data := []byte{}
+	rotated := map[uint]references.ContentKey{}
+	rotateFn := func(contentKey references.ContentKey) (references.ContentKey, error) {
+		if !contentKey.HasKey() || *contentKey.Key() != oldKeyID {
+			return contentKey, nil
+		}
+
+		from := contentKey.Content().From()
+		if updatedContentKey, ok := rotated[from]; ok {
+			return updatedContentKey, nil
+		}
+
+		if uint(len(data)) >= app.rotationBatchSize {
+			isDone = false
+			return contentKey, nil
+		}
+
+		updatedContentKey, encrypted, err := app.rotateContentKey(pContext, contentKey, newKeyID, dataLength+uint(len(data)))
+		if err != nil {
+			return nil, err
+		}
+
+		rotated[from] = updatedContentKey
+		data = append(data, encrypted...)
+		return updatedContentKey, nil
+	}
+
+	updatedContentKeys, err := app.rotateContentKeys(contentKeysList, rotateFn)
+	if err != nil {
+		return false, err
+	}
+
+	updatedResources := []references.Resource{}
+	if pContext.reference.HasResources() {
+		for _, oneResource := range pContext.reference.Resources().List() {
+			updatedResource, err := app.rotateResource(oneResource, rotateFn)
+			if err != nil {
+				return false, err
+			}
+
+			updatedResources = append(updatedResources, updatedResource)
+		}
+	}
+
+	isReferenceRotated := pContext.pReferenceKey == nil || *pContext.pReferenceKey != oldKeyID
+	if len(data) <= 0 && isReferenceRotated {
+		return true, nil
+	}
+
+	builder := app.referenceBuilder.Create().WithCommits(pContext.reference.Commits())
+	if len(updatedContentKeys) > 0 {
+		contentKeys, err := app.referenceContentKeysBuilder.Create().
+			WithList(updatedContentKeys).
+			Now()
+
+		if err != nil {
+			return false, err
+		}
+
+		builder.WithContentKeys(contentKeys)
+	}
+
+	if len(updatedResources) > 0 {
+		resources, err := app.referenceResourcesBuilder.Create().
+			WithList(updatedResources).
+			Now()
+
+		if err != nil {
+			return false, err
+		}
+
+		builder.WithResources(resources)
+	}
+
+	if pContext.reference.HasTags() {
+		builder.WithTags(pContext.reference.Tags())
+	}
+
+	if pContext.reference.HasKinds() {
+		builder.WithKinds(pContext.reference.Kinds())
+	}
+
+	reference, err := builder.Now()
+	if err != nil {
+		return false, err
+	}
+
+	referenceBytes, err := app.referenceAdapter.ToContent(reference)
+	if err != nil {
+		return false, err
+	}
+
+	referenceBytes, err = app.encryptReferenceWithKey(newKeyID, referenceBytes)
+	if err != nil {
+		return false, err
+	}
+
+	err = app.amend(pContext, referenceBytes, data)
+	if err != nil {
+		return false, err
+	}
+
+	return isDone, nil
+}
+
+// rotateContentKey re-encrypts the content using the new key, then returns its content key once its data is appended at the given position
+func (app *application) rotateContentKey(pContext *context, contentKey references.ContentKey, newKeyID uint, from uint) (references.ContentKey, []byte, error) {
+	pointer := contentKey.Content()
+	offset := pContext.dataOffset + pointer.From()
+	encrypted, err := app.read(pContext.identifier, offset, pointer.Length())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = verifyContent(contentKey, offset, encrypted)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := app.decryptContent(contentKey, encrypted)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replacement, err := app.encrypt(newKeyID, data, createContentAdditionalData(contentKey.Kind(), contentKey.Hash()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uint(len(replacement)) != pointer.Length() {
+		str := fmt.Sprintf("the re-encrypted content (kind: %d, hash: %s) was expected to contain %d bytes, %d returned", contentKey.Kind(), contentKey.Hash().String(), pointer.Length(), len(replacement))
+		return nil, nil, errors.New(str)
+	}
+
+	updatedPointer, err := app.referencePointerBuilder.Create().
+		From(from).
+		WithLength(pointer.Length()).
+		WithChecksum(computeChecksum(replacement)).
+		Now()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updatedContentKey, err := app.referenceContentKeyBuilder.Create().
+		WithHash(contentKey.Hash()).
+		WithKind(contentKey.Kind()).
+		WithContent(updatedPointer).
+		WithCommit(contentKey.Commit()).
+		WithCodec(contentKey.Codec()).
+		WithKey(newKeyID).
+		Now()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return updatedContentKey, replacement, nil
+}
+
+func (app *application) rotateContentKeys(contentKeys []references.ContentKey, rotateFn func(contentKey references.ContentKey) (references.ContentKey, error)) ([]references.ContentKey, error) {
+	output := []references.ContentKey{}
+	for _, oneContentKey := range contentKeys {
+		updatedContentKey, err := rotateFn(oneContentKey)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, updatedContentKey)
+	}
+
+	return output, nil
+}
+
+// rotateResource rotates the content keys of a deletes resource, the other resources are returned as is
+func (app *application) rotateResource(resource references.Resource, rotateFn func(contentKey references.ContentKey) (references.ContentKey, error)) (references.Resource, error) {
+	if !strings.HasPrefix(resource.Name(), createDeletesPrefix()) {
+		return resource, nil
+	}
+
+	contentKeys, err := app.referenceContentKeysAdapter.ToContentKeys(resource.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	contentKeysList, err := app.rotateContentKeys(contentKeys.List(), rotateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedContentKeys, err := app.referenceContentKeysBuilder.Create().
+		WithList(contentKeysList).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := app.referenceContentKeysAdapter.ToContent(updatedContentKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.referenceResourceBuilder.Create().
+		WithName(resource.Name()).
+		WithData(data).
+		Now()
+}
@@ -0,0 +1,336 @@
+package files
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+func TestRotateKey_withInterruption_thenResume_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	oldKey := []byte("this is a 32 bytes long key.....")
+	newKey := []byte("this is another 32 bytes key....")
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+		contents.NewContentForTests(0, []byte("this is the second data")),
+		contents.NewContentForTests(1, []byte("this is the third data")),
+		contents.NewContentForTests(1, []byte("this is the fourth data")),
+	}
+
+	// commit the first contents using the old key:
+	app := newApplicationForTests(t, dirPath, withRotationForTests(NewKeyProvider(oldKey)))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, list[:2]) {
+		return
+	}
+
+	// commit the last contents using the new key, the database is then encrypted using both keys:
+	keys := map[uint][]byte{
+		0: oldKey,
+		1: newKey,
+	}
+
+	app = newApplicationForTests(t, dirPath, withRotationForTests(NewKeyRingProvider(1, keys)))
+	if app == nil {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, list[2:]) {
+		return
+	}
+
+	if !retrieveAllForTests(t, app, name, list) {
+		return
+	}
+
+	// interrupt the rotation after the first batch, since every batch re-encrypts a single content:
+	interruptedApp := newApplicationForTests(t, dirPath, withRotationForTests(&interruptedKeyProviderForTests{
+		KeyProvider: NewKeyRingProvider(1, keys),
+		remaining:   6,
+	}))
+
+	if interruptedApp == nil {
+		return
+	}
+
+	err = interruptedApp.RotateKey(name, 0, 1)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the database remains readable using both keys:
+	if !retrieveAllForTests(t, app, name, list) {
+		return
+	}
+
+	pAmount := amountEncryptedWithKeyForTests(t, app, name, 0)
+	if pAmount == nil {
+		return
+	}
+
+	if *pAmount != 1 {
+		t.Errorf("%d content was expected to remain encrypted using the old key, %d returned", 1, *pAmount)
+		return
+	}
+
+	// resume the rotation:
+	err = app.RotateKey(name, 0, 1)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pAmount = amountEncryptedWithKeyForTests(t, app, name, 0)
+	if pAmount == nil {
+		return
+	}
+
+	if *pAmount != 0 {
+		t.Errorf("%d content was expected to remain encrypted using the old key, %d returned", 0, *pAmount)
+		return
+	}
+
+	// the old key is no longer needed:
+	newApp := newApplicationForTests(t, dirPath, withRotationForTests(NewKeyRingProvider(1, map[uint][]byte{
+		1: newKey,
+	})))
+
+	if newApp == nil {
+		return
+	}
+
+	retrieveAllForTests(t, newApp, name, list)
+}
+
+func TestRotateKey_withTaggedDeletedContent_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	oldKey := []byte("this is a 32 bytes long key.....")
+	newKey := []byte("this is another 32 bytes key....")
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+		contents.NewContentForTests(0, []byte("this is the second data")),
+	}
+
+	app := newApplicationForTests(t, dirPath, withRotationForTests(NewKeyProvider(oldKey)))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, list) {
+		return
+	}
+
+	// tag the commit, then delete the first content so that only the tag pins it:
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Tag(*pContext, "v1", reference.Commits().Latest().Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Erase(*pContext, list[0].Kind(), list[0].Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	app.Close(*pContext)
+	pAmount := amountEncryptedWithKeyForTests(t, app, name, 0)
+	if pAmount == nil {
+		return
+	}
+
+	if *pAmount != 2 {
+		t.Errorf("%d contents were expected to be encrypted using the old key, %d returned", 2, *pAmount)
+		return
+	}
+
+	// rotate, then read the tagged contents using the new key only:
+	app = newApplicationForTests(t, dirPath, withRotationForTests(NewKeyRingProvider(1, map[uint][]byte{
+		0: oldKey,
+		1: newKey,
+	})))
+
+	if app == nil {
+		return
+	}
+
+	err = app.RotateKey(name, 0, 1)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the deleted content is no longer encrypted using the old key in the deletes resources:
+	pAmount = amountEncryptedWithKeyForTests(t, app, name, 0)
+	if pAmount == nil {
+		return
+	}
+
+	if *pAmount != 0 {
+		t.Errorf("%d content was expected to remain encrypted using the old key, %d returned", 0, *pAmount)
+		return
+	}
+
+	newApp := newApplicationForTests(t, dirPath, withRotationForTests(NewKeyRingProvider(1, map[uint][]byte{
+		1: newKey,
+	})))
+
+	if newApp == nil {
+		return
+	}
+
+	pTagContext, err := newApp.OpenTag(name, "v1")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer newApp.Close(*pTagContext)
+	for _, oneContent := range list {
+		_, err := newApp.Retrieve(*pTagContext, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+}
+
+func TestRotateKey_withoutKeyProvider_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.RotateKey("my_name", 0, 1)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+type interruptedKeyProviderForTests struct {
+	KeyProvider
+	remaining uint
+}
+
+// Fetch fetches a key until the remaining amount of fetches is exhausted
+func (obj *interruptedKeyProviderForTests) Fetch(id uint) ([]byte, error) {
+	if obj.remaining <= 0 {
+		return nil, errors.New("the key provider is no longer available")
+	}
+
+	obj.remaining--
+	return obj.KeyProvider.Fetch(id)
+}
+
+func amountEncryptedWithKeyForTests(t *testing.T, app Application, name string, keyID uint) *uint {
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	defer app.Close(*pContext)
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	contentKeysList := []references.ContentKey{}
+	if reference.HasContentKeys() {
+		contentKeysList = reference.ContentKeys().List()
+	}
+
+	// the deleted contents remain listed in the deletes resources:
+	if reference.HasResources() {
+		for _, oneResource := range reference.Resources().List() {
+			if !strings.HasPrefix(oneResource.Name(), createDeletesPrefix()) {
+				continue
+			}
+
+			contentKeys, err := references.NewContentKeysAdapter().ToContentKeys(oneResource.Data())
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return nil
+			}
+
+			contentKeysList = append(contentKeysList, contentKeys.List()...)
+		}
+	}
+
+	amount := uint(0)
+	for _, oneContentKey := range contentKeysList {
+		if oneContentKey.HasKey() && *oneContentKey.Key() == keyID {
+			amount++
+		}
+	}
+
+	return &amount
+}
+
+func withRotationForTests(keyProvider KeyProvider) func(builder ApplicationBuilder) {
+	return func(builder ApplicationBuilder) {
+		builder.WithRotationBatchSize(10).
+			WithKeyProvider(keyProvider)
+	}
+}
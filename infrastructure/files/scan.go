@@ -0,0 +1,371 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+// the scan resource of a kind lists the hashes of its contents sorted by hash.  The content keys of a kind are listed
+// in the order of the commits that inserted them, therefore the order by commit is not stored:
+const scanResourceKeyword = "scan"
+const scanEntryLength = hash.Size
+
+// scanPosition represents the position of a content key in a scan
+type scanPosition struct {
+	commitIndex uint64
+	hash        []byte
+}
+
+// Scan returns a page of the committed content keys of the kind, in order, after the cursor of the options
+func (app *application) Scan(context uint, kind uint, options databases.ScanOptions) ([]references.ContentKey, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if options.Order == databases.OrderByHash {
+			return app.scanByHash(pContext, kind, options)
+		}
+
+		if options.Order == databases.OrderByCommit {
+			return app.scanByCommit(pContext, kind, options)
+		}
+
+		str := fmt.Sprintf("the scan order (%d) is invalid", options.Order)
+		return nil, errors.New(str)
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Scan using this context", context)
+	return nil, errors.New(str)
+}
+
+// scanByHash returns a page of the content keys of the kind sorted by hash, seeking the cursor in the stored scan
+func (app *application) scanByHash(pContext *context, kind uint, options databases.ScanOptions) ([]references.ContentKey, error) {
+	entries, err := app.fetchScan(pContext, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := len(entries) / scanEntryLength
+	beginsOn := 0
+	if options.After != nil {
+		// the cursor may have been deleted since, therefore it is searched by position rather than by identity:
+		after := options.After.Hash()
+		beginsOn = sort.Search(amount, func(index int) bool {
+			return bytes.Compare(decodeScanEntry(entries, index), after) > 0
+		})
+	}
+
+	endsOn := amount
+	if options.Limit > 0 && uint(endsOn-beginsOn) > options.Limit {
+		endsOn = beginsOn + int(options.Limit)
+	}
+
+	output := []references.ContentKey{}
+	for index := beginsOn; index < endsOn; index++ {
+		contentKey, err := pContext.reference.ContentKeys().Fetch(kind, decodeScanEntry(entries, index))
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, contentKey)
+	}
+
+	return output, nil
+}
+
+// scanByCommit returns a page of the content keys of the kind sorted by commit, then by hash.  The content keys of
+// the kind are listed by commit, therefore the commit of the cursor is searched in the list, then only the contents of
+// the commits of the page are sorted by hash
+func (app *application) scanByCommit(pContext *context, kind uint, options databases.ScanOptions) ([]references.ContentKey, error) {
+	output := []references.ContentKey{}
+	if pContext.reference == nil || !pContext.reference.HasContentKeys() {
+		return output, nil
+	}
+
+	// the kind does not contain any content:
+	contentKeys, err := pContext.reference.ContentKeys().ListByKind(kind)
+	if err != nil {
+		return output, nil
+	}
+
+	commitIndexes := createCommitIndexes(pContext.reference)
+	positionFn := func(index int) (scanPosition, error) {
+		return createScanPosition(commitIndexes, contentKeys[index])
+	}
+
+	beginsOn := 0
+	var pAfter *scanPosition
+	if options.After != nil {
+		after, err := createScanPosition(commitIndexes, options.After)
+		if err != nil {
+			return nil, err
+		}
+
+		var searchErr error
+		beginsOn = sort.Search(len(contentKeys), func(index int) bool {
+			position, err := positionFn(index)
+			if err != nil {
+				searchErr = err
+				return true
+			}
+
+			return position.commitIndex >= after.commitIndex
+		})
+
+		if searchErr != nil {
+			return nil, searchErr
+		}
+
+		pAfter = &after
+	}
+
+	for beginsOn < len(contentKeys) {
+		if options.Limit > 0 && uint(len(output)) >= options.Limit {
+			break
+		}
+
+		first, err := positionFn(beginsOn)
+		if err != nil {
+			return nil, err
+		}
+
+		endsOn := beginsOn + 1
+		for endsOn < len(contentKeys) {
+			position, err := positionFn(endsOn)
+			if err != nil {
+				return nil, err
+			}
+
+			if position.commitIndex != first.commitIndex {
+				break
+			}
+
+			endsOn++
+		}
+
+		group := append([]references.ContentKey{}, contentKeys[beginsOn:endsOn]...)
+		sort.Slice(group, func(i int, j int) bool {
+			return bytes.Compare(group[i].Hash(), group[j].Hash()) < 0
+		})
+
+		for _, oneContentKey := range group {
+			if options.Limit > 0 && uint(len(output)) >= options.Limit {
+				break
+			}
+
+			position := scanPosition{
+				commitIndex: first.commitIndex,
+				hash:        oneContentKey.Hash(),
+			}
+
+			if pAfter != nil && compareScanPositions(position, *pAfter) <= 0 {
+				continue
+			}
+
+			output = append(output, oneContentKey)
+		}
+
+		beginsOn = endsOn
+	}
+
+	return output, nil
+}
+
+// fetchScan returns the encoded hashes of the kind, sorted.  The hashes are sorted from the committed contents when
+// the database does not contain them yet, or when the context is opened on a tag, since the stored scans follow the
+// latest commit
+func (app *application) fetchScan(pContext *context, kind uint) ([]byte, error) {
+	if pContext.pTag == nil && pContext.reference != nil && pContext.reference.HasResources() {
+		resource, err := pContext.reference.Resources().Fetch(createScanName(kind))
+		if err == nil {
+			return resource.Data(), verifyScanEntries(resource.Data())
+		}
+	}
+
+	hashes := [][]byte{}
+	if pContext.reference != nil && pContext.reference.HasContentKeys() {
+		// the kind does not contain any content:
+		contentKeys, err := pContext.reference.ContentKeys().ListByKind(kind)
+		if err == nil {
+			for _, oneContentKey := range contentKeys {
+				hashes = append(hashes, oneContentKey.Hash())
+			}
+		}
+	}
+
+	sort.Slice(hashes, func(i int, j int) bool {
+		return bytes.Compare(hashes[i], hashes[j]) < 0
+	})
+
+	return encodeScanEntries(hashes), nil
+}
+
+// updateScans returns the resources in which the scans of the kinds of the pending inserts and deletes are updated,
+// by merging the sorted hashes of the inserts into the stored scan.  Returns nil when there is no resource
+func (app *application) updateScans(pContext *context, resources references.Resources) (references.Resources, error) {
+	inserts := map[uint][][]byte{}
+	for _, oneContent := range pContext.insertList {
+		inserts[oneContent.Kind()] = append(inserts[oneContent.Kind()], oneContent.Hash())
+	}
+
+	for _, oneContentKey := range pContext.delList {
+		if _, ok := inserts[oneContentKey.Kind()]; !ok {
+			inserts[oneContentKey.Kind()] = [][]byte{}
+		}
+	}
+
+	if len(inserts) <= 0 {
+		return resources, nil
+	}
+
+	kinds := []uint{}
+	for oneKind := range inserts {
+		kinds = append(kinds, oneKind)
+	}
+
+	sort.Slice(kinds, func(i int, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	resourcesList := []references.Resource{}
+	if resources != nil {
+		resourcesList = append(resourcesList, resources.List()...)
+	}
+
+	for _, oneKind := range kinds {
+		entries, err := app.fetchScan(pContext, oneKind)
+		if err != nil {
+			return nil, err
+		}
+
+		inserted := inserts[oneKind]
+		sort.Slice(inserted, func(i int, j int) bool {
+			return bytes.Compare(inserted[i], inserted[j]) < 0
+		})
+
+		merged := [][]byte{}
+		amount := len(entries) / scanEntryLength
+		insertIndex := 0
+		for index := 0; index < amount; index++ {
+			entry := decodeScanEntry(entries, index)
+			if _, ok := pContext.delList[createKeyname(oneKind, entry)]; ok {
+				continue
+			}
+
+			for insertIndex < len(inserted) && bytes.Compare(inserted[insertIndex], entry) < 0 {
+				merged = append(merged, inserted[insertIndex])
+				insertIndex++
+			}
+
+			merged = append(merged, entry)
+		}
+
+		merged = append(merged, inserted[insertIndex:]...)
+
+		// a resource cannot be empty, therefore the scan of an emptied kind is removed:
+		if len(merged) <= 0 {
+			resourcesList = removeResource(resourcesList, createScanName(oneKind))
+			continue
+		}
+
+		resource, err := app.referenceResourceBuilder.Create().
+			WithName(createScanName(oneKind)).
+			WithData(encodeScanEntries(merged)).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		resourcesList = replaceResource(resourcesList, resource)
+	}
+
+	if len(resourcesList) <= 0 {
+		return nil, nil
+	}
+
+	return app.referenceResourcesBuilder.Create().
+		WithList(resourcesList).
+		Now()
+}
+
+// createScanPosition returns the position of the content key, using the index of the commit that inserted it
+func createScanPosition(commitIndexes map[string]uint64, contentKey references.ContentKey) (scanPosition, error) {
+	if commitIndex, ok := commitIndexes[contentKey.Commit().String()]; ok {
+		return scanPosition{
+			commitIndex: commitIndex,
+			hash:        contentKey.Hash(),
+		}, nil
+	}
+
+	str := fmt.Sprintf("the commit (hash: %s) of the content key (hash: %s) does not exists", contentKey.Commit().String(), contentKey.Hash().String())
+	return scanPosition{}, errors.New(str)
+}
+
+// createCommitIndexes returns the index of every commit of the reference, by hash
+func createCommitIndexes(reference references.Reference) map[string]uint64 {
+	output := map[string]uint64{}
+	for index, oneCommit := range reference.Commits().List() {
+		output[oneCommit.Hash().String()] = uint64(index)
+	}
+
+	return output
+}
+
+func removeResource(list []references.Resource, name string) []references.Resource {
+	output := []references.Resource{}
+	for _, oneResource := range list {
+		if oneResource.Name() == name {
+			continue
+		}
+
+		output = append(output, oneResource)
+	}
+
+	return output
+}
+
+func compareScanPositions(first scanPosition, second scanPosition) int {
+	if first.commitIndex != second.commitIndex {
+		if first.commitIndex < second.commitIndex {
+			return -1
+		}
+
+		return 1
+	}
+
+	return bytes.Compare(first.hash, second.hash)
+}
+
+// encodeScanEntries encodes the sorted hashes
+func encodeScanEntries(hashes [][]byte) []byte {
+	output := []byte{}
+	for _, oneHash := range hashes {
+		output = append(output, oneHash...)
+	}
+
+	return output
+}
+
+// decodeScanEntry decodes the hash at the given index, without decoding the other entries
+func decodeScanEntry(entries []byte, index int) []byte {
+	beginsOn := index * scanEntryLength
+	return entries[beginsOn : beginsOn+scanEntryLength]
+}
+
+func verifyScanEntries(entries []byte) error {
+	if len(entries)%scanEntryLength != 0 {
+		str := fmt.Sprintf("the scan was expected to contain a multiple of %d bytes, %d provided", scanEntryLength, len(entries))
+		return errors.New(str)
+	}
+
+	return nil
+}
+
+func createScanName(kind uint) string {
+	return fmt.Sprintf("%s%s%d", scanResourceKeyword, fileNameExtensionDelimiter, kind)
+}
@@ -0,0 +1,384 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestScan_pagesThroughPersistedOrder_afterDeletedCursor_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+		contents.NewContentForTests(0, []byte("this is the second data")),
+		contents.NewContentForTests(1, []byte("this is another kind")),
+		contents.NewContentForTests(0, []byte("this is the third data")),
+		contents.NewContentForTests(0, []byte("this is the fourth data")),
+	}
+
+	if !insertThenCommitForTests(t, app, name, list[:3]) {
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, list[3:]) {
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+
+	// the hash order of the kind is persisted in the database, the commit order being the order of its content keys:
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	resource, err := reference.Resources().Fetch(createScanName(0))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(resource.Data()) != 4*scanEntryLength {
+		t.Errorf("the scan was expected to contain %d bytes, %d returned", 4*scanEntryLength, len(resource.Data()))
+		return
+	}
+
+	firstPage, err := app.Scan(*pContext, 0, databases.ScanOptions{
+		Order: databases.OrderByCommit,
+		Limit: 2,
+	})
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(firstPage) != 2 {
+		t.Errorf("the page was expected to contain %d content keys, %d returned", 2, len(firstPage))
+		return
+	}
+
+	for _, oneContentKey := range firstPage {
+		if !oneContentKey.Commit().Compare(reference.Commits().List()[0].Hash()) {
+			t.Errorf("the first page was expected to contain the contents of the first commit")
+			return
+		}
+	}
+
+	// erase the cursor, the next page begins after its position:
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	cursor := firstPage[1]
+	err = app.Erase(*pContext, cursor.Kind(), cursor.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	secondPage, err := app.Scan(*pContext, 0, databases.ScanOptions{
+		Order: databases.OrderByCommit,
+		After: cursor,
+	})
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(secondPage) != 2 {
+		t.Errorf("the page was expected to contain %d content keys, %d returned", 2, len(secondPage))
+		return
+	}
+
+	for _, oneContentKey := range secondPage {
+		if oneContentKey.Hash().Compare(cursor.Hash()) || oneContentKey.Hash().Compare(firstPage[0].Hash()) {
+			t.Errorf("the second page was expected to begin after the cursor")
+			return
+		}
+	}
+}
+
+func TestScan_byHash_thenByCommit_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+
+	// an empty kind contains no content key:
+	list, err := app.Scan(*pContext, 0, databases.ScanOptions{})
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(list) != 0 {
+		t.Errorf("the scan was expected to return no content key, %d returned", len(list))
+		return
+	}
+
+	first := []contents.Content{}
+	for i := 0; i < 5; i++ {
+		first = append(first, contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the first commit data %d", i))))
+	}
+
+	second := []contents.Content{}
+	for i := 0; i < 3; i++ {
+		second = append(second, contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the second commit data %d", i))))
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, append(first, contents.NewContentForTests(1, []byte("this is another kind")))) {
+		return
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, second) {
+		return
+	}
+
+	// page by hash:
+	pages := [][]byte{}
+	options := databases.ScanOptions{
+		Limit: 3,
+		Order: databases.OrderByHash,
+	}
+
+	for {
+		page, err := app.Scan(*pContext, 0, options)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if len(page) <= 0 {
+			break
+		}
+
+		for _, oneContentKey := range page {
+			pages = append(pages, oneContentKey.Hash())
+		}
+
+		options.After = page[len(page)-1]
+	}
+
+	if len(pages) != len(first)+len(second) {
+		t.Errorf("the scan was expected to return %d content keys, %d returned", len(first)+len(second), len(pages))
+		return
+	}
+
+	for index := 1; index < len(pages); index++ {
+		if bytes.Compare(pages[index-1], pages[index]) >= 0 {
+			t.Errorf("the content keys were expected to be ordered by hash")
+			return
+		}
+	}
+
+	// page by commit, the cursor remains valid once deleted and the new contents come last:
+	page, err := app.Scan(*pContext, 0, databases.ScanOptions{
+		Limit: 5,
+		Order: databases.OrderByCommit,
+	})
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(page) != 5 {
+		t.Errorf("the scan was expected to return %d content keys, %d returned", 5, len(page))
+		return
+	}
+
+	for index, oneContentKey := range page {
+		if !oneContentKey.Commit().Compare(page[0].Commit()) {
+			t.Errorf("the first page was expected to contain the contents of the first commit")
+			return
+		}
+
+		if index > 0 && bytes.Compare(page[index-1].Hash(), oneContentKey.Hash()) >= 0 {
+			t.Errorf("the content keys of a commit were expected to be ordered by hash")
+			return
+		}
+	}
+
+	cursor := page[len(page)-1]
+	err = app.Erase(*pContext, 0, cursor.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	third := contents.NewContentForTests(0, []byte("this is the third commit data"))
+	err = app.Insert(*pContext, third)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	page, err = app.Scan(*pContext, 0, databases.ScanOptions{
+		After: cursor,
+		Order: databases.OrderByCommit,
+	})
+
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(page) != len(second)+1 {
+		t.Errorf("the scan was expected to return %d content keys, %d returned", len(second)+1, len(page))
+		return
+	}
+
+	if !page[len(page)-1].Hash().Compare(third.Hash()) {
+		t.Errorf("the last content key was expected to be the content of the latest commit")
+		return
+	}
+}
+
+func TestScan_onTag_byCommit_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	pContext := openThenLockForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	first := []contents.Content{}
+	for i := 0; i < 4; i++ {
+		first = append(first, contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the first commit data %d", i))))
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, first) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	firstCommit := reference.Commits().Latest().Hash()
+	second := contents.NewContentForTests(0, []byte("this is the second commit data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{second}) {
+		return
+	}
+
+	// the content erased after the tagged commit is restored at the position of its commit:
+	err = app.Erase(*pContext, first[0].Kind(), first[0].Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Tag(*pContext, "v1", firstCommit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pTagContext, err := app.OpenTag(name, "v1")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pTagContext)
+	for _, oneOrder := range []uint{databases.OrderByHash, databases.OrderByCommit} {
+		page, err := app.Scan(*pTagContext, 0, databases.ScanOptions{
+			Order: oneOrder,
+		})
+
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if len(page) != len(first) {
+			t.Errorf("the scan (order: %d) was expected to return %d content keys, %d returned", oneOrder, len(first), len(page))
+			return
+		}
+
+		for index := 1; index < len(page); index++ {
+			if bytes.Compare(page[index-1].Hash(), page[index].Hash()) >= 0 {
+				t.Errorf("the content keys of the scan (order: %d) were expected to be ordered by hash", oneOrder)
+				return
+			}
+		}
+	}
+}
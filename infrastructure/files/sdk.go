@@ -1,28 +1,195 @@
 package files
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
 	databases "github.com/steve-care-software/databases/applications"
 	"github.com/steve-care-software/databases/domain/contents"
 	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
 	"github.com/steve-care-software/libs/cryptography/trees"
 )
 
 const fileNameExtensionDelimiter = "."
+const lockExtension = "lock"
 const expectedReferenceBytesLength = 8
+const formatVersion uint32 = 1
 const filePermission = 0777
+const defaultRotationBatchSize = 4 * 1024 * 1024
+
+const (
+	// CodecNone represents uncompressed contents
+	CodecNone uint = iota
+
+	// CodecGzip represents contents compressed using gzip
+	CodecGzip
+
+	// CodecZstd represents contents compressed using zstd
+	CodecZstd
+
+	// CodecSnappy represents contents compressed using snappy
+	CodecSnappy
+
+	// CodecZstdDictionary represents contents compressed using zstd and a dictionary trained on the contents of their kind
+	CodecZstdDictionary
+)
+
+// ErrAuthentication is returned when encrypted data cannot be authenticated, generally because the key is invalid
+var ErrAuthentication = errors.New("the encrypted data could not be authenticated using the provided key")
+
+// ErrOutdatedFormat is returned when a database is in a previous format version, it must then be migrated
+var ErrOutdatedFormat = errors.New("the database is in an outdated format and must be migrated")
+
+// ErrUnsupportedFormat is returned when a database is in a format version more recent than the supported one
+var ErrUnsupportedFormat = errors.New("the database is in an unsupported format")
 
-// NewApplication creates a new file application instance
+// ErrCorrupted is returned when stored data does not match its checksum
+var ErrCorrupted = errors.New("the stored data does not match its checksum")
+
+// CorruptedError is returned when a stored content does not match its checksum, it matches ErrCorrupted
+type CorruptedError struct {
+	Kind   uint
+	Hash   hash.Hash
+	Offset uint
+}
+
+// ErrConflict is returned when the database was changed by another writer since the context read it, the context
+// must then be refreshed before writing again
+var ErrConflict = errors.New("the database was changed since the context read it")
+
+// ErrUnsignedCommit is returned when a database contains an unsigned commit while signed commits are required
+var ErrUnsignedCommit = errors.New("the commit is not signed")
+
+// ErrUntrustedCommit is returned when a database contains a commit signed by an author outside of the trusted authors
+var ErrUntrustedCommit = errors.New("the commit is signed by an untrusted author")
+
+// NewApplication creates a new file application instance on the OS filesystem
 func NewApplication(
 	dirPath string,
 	dstExtension string,
 	bckExtension string,
 	readChunkSize uint,
 	onOpenFn databases.OnOpenFn,
-) databases.Application {
+	onBeforeCommitFn databases.OnBeforeCommitFn,
+	onAfterCommitFn databases.OnAfterCommitFn,
+	instrumentation databases.Instrumentation,
+	keyProvider KeyProvider,
+) (Application, error) {
+	storage := NewOSStorage()
+	return NewApplicationWithStorage(
+		storage,
+		dirPath,
+		dstExtension,
+		bckExtension,
+		readChunkSize,
+		onOpenFn,
+		onBeforeCommitFn,
+		onAfterCommitFn,
+		instrumentation,
+		keyProvider,
+	)
+}
+
+// NewApplicationWithStorage creates a new file application instance on the given storage
+func NewApplicationWithStorage(
+	storage Storage,
+	dirPath string,
+	dstExtension string,
+	bckExtension string,
+	readChunkSize uint,
+	onOpenFn databases.OnOpenFn,
+	onBeforeCommitFn databases.OnBeforeCommitFn,
+	onAfterCommitFn databases.OnAfterCommitFn,
+	instrumentation databases.Instrumentation,
+	keyProvider KeyProvider,
+) (Application, error) {
+	return newApplication(
+		storage,
+		dirPath,
+		dstExtension,
+		bckExtension,
+		readChunkSize,
+		onOpenFn,
+		onBeforeCommitFn,
+		onAfterCommitFn,
+		instrumentation,
+		keyProvider,
+		map[uint]uint{},
+		nil,
+		[]ed25519.PublicKey{},
+		false,
+		map[uint]map[string]IndexFn{},
+		0,
+		defaultRotationBatchSize,
+	)
+}
+
+// NewApplicationBuilder creates a new application builder instance
+func NewApplicationBuilder() ApplicationBuilder {
+	return createApplicationBuilder()
+}
+
+// NewOSStorage creates a new storage instance on the OS filesystem
+func NewOSStorage() Storage {
+	return createOSStorage()
+}
+
+// NewKeyProvider creates a new key provider instance that contains a single key, identified by zero (0)
+func NewKeyProvider(key []byte) KeyProvider {
+	return createKeyProvider(0, map[uint][]byte{
+		0: key,
+	})
+}
+
+// NewKeyRingProvider creates a new key provider instance that contains many keys, the current one being used to encrypt
+func NewKeyRingProvider(current uint, keys map[uint][]byte) KeyProvider {
+	return createKeyProvider(current, keys)
+}
+
+func newApplication(
+	storage Storage,
+	dirPath string,
+	dstExtension string,
+	bckExtension string,
+	readChunkSize uint,
+	onOpenFn databases.OnOpenFn,
+	onBeforeCommitFn databases.OnBeforeCommitFn,
+	onAfterCommitFn databases.OnAfterCommitFn,
+	instrumentation databases.Instrumentation,
+	keyProvider KeyProvider,
+	compressions map[uint]uint,
+	signer crypto.Signer,
+	trustedAuthors []ed25519.PublicKey,
+	isSignatureRequired bool,
+	indexes map[uint]map[string]IndexFn,
+	readCacheCapacity uint,
+	rotationBatchSize uint,
+) (Application, error) {
+	if readChunkSize <= 0 {
+		return nil, errors.New("the read chunk size must be greater than zero (0) in order to build an Application instance")
+	}
+
+	if dstExtension == bckExtension {
+		return nil, errors.New("the destination and backup extensions must be different in order to build an Application instance")
+	}
+
+	for _, oneExtension := range []string{lockExtension, journalExtension} {
+		if dstExtension == oneExtension || bckExtension == oneExtension {
+			str := fmt.Sprintf("the destination and backup extensions must be different than the reserved extension (%s) in order to build an Application instance", oneExtension)
+			return nil, errors.New(str)
+		}
+	}
+
 	contentsBuilder := contents.NewBuilder()
 	contentBuilder := contents.NewContentBuilder()
 	referenceAdapter := references.NewAdapter()
 	referenceBuilder := references.NewBuilder()
+	referenceContentKeysAdapter := references.NewContentKeysAdapter()
+	referenceOriginalAdapter := references.NewOriginalAdapter()
 	referenceContentKeysBuilder := references.NewContentKeysBuilder()
 	referenceContentKeyBuilder := references.NewContentKeyBuilder()
 	referenceCommitsBuilder := references.NewCommitsBuilder()
@@ -30,13 +197,43 @@ func NewApplication(
 	referenceCommitBuilder := references.NewCommitBuilder()
 	referenceActionBuilder := references.NewActionBuilder()
 	referencePointerBuilder := references.NewPointerBuilder()
+	referenceResourcesBuilder := references.NewResourcesBuilder()
+	referenceResourceBuilder := references.NewResourceBuilder()
+	referenceTagsBuilder := references.NewTagsBuilder()
+	referenceTagBuilder := references.NewTagBuilder()
+	referenceKindsBuilder := references.NewKindsBuilder()
 	hashTreeBuilder := trees.NewBuilder()
+	codecs := createCodecs()
+	var pReadCache *readCache
+	if readCacheCapacity > 0 {
+		pReadCache = createReadCache(readCacheCapacity)
+	}
+
+	trusted := map[string]bool{}
+	for _, oneAuthor := range trustedAuthors {
+		trusted[string(oneAuthor)] = true
+	}
+
 	return createApplication(
+		storage,
 		onOpenFn,
+		onBeforeCommitFn,
+		onAfterCommitFn,
+		instrumentation,
+		keyProvider,
+		compressions,
+		codecs,
+		signer,
+		trusted,
+		isSignatureRequired,
+		indexes,
+		pReadCache,
 		contentsBuilder,
 		contentBuilder,
 		referenceAdapter,
 		referenceBuilder,
+		referenceContentKeysAdapter,
+		referenceOriginalAdapter,
 		referenceContentKeysBuilder,
 		referenceContentKeyBuilder,
 		referenceCommitsBuilder,
@@ -44,10 +241,126 @@ func NewApplication(
 		referenceCommitBuilder,
 		referenceActionBuilder,
 		referencePointerBuilder,
+		referenceResourcesBuilder,
+		referenceResourceBuilder,
+		referenceTagsBuilder,
+		referenceTagBuilder,
+		referenceKindsBuilder,
 		hashTreeBuilder,
 		dirPath,
 		dstExtension,
 		bckExtension,
 		readChunkSize,
-	)
+		rotationBatchSize,
+	), nil
+}
+
+// ApplicationBuilder represents a file application builder
+type ApplicationBuilder interface {
+	Create() ApplicationBuilder
+	WithStorage(storage Storage) ApplicationBuilder
+	WithDirPath(dirPath string) ApplicationBuilder
+	WithDestinationExtension(dstExtension string) ApplicationBuilder
+	WithBackupExtension(bckExtension string) ApplicationBuilder
+	WithReadChunkSize(readChunkSize uint) ApplicationBuilder
+	WithOnOpen(onOpenFn databases.OnOpenFn) ApplicationBuilder
+	WithOnBeforeCommit(onBeforeCommitFn databases.OnBeforeCommitFn) ApplicationBuilder
+	WithOnAfterCommit(onAfterCommitFn databases.OnAfterCommitFn) ApplicationBuilder
+	WithInstrumentation(instrumentation databases.Instrumentation) ApplicationBuilder
+	WithKeyProvider(keyProvider KeyProvider) ApplicationBuilder
+	WithCompression(kind uint, codec uint) ApplicationBuilder
+	WithSigner(signer crypto.Signer) ApplicationBuilder
+	WithTrustedAuthors(authors []ed25519.PublicKey) ApplicationBuilder
+	RequireSignedCommits() ApplicationBuilder
+	WithIndex(kind uint, name string, fn IndexFn) ApplicationBuilder
+	WithReadCache(capacity uint) ApplicationBuilder
+	WithRotationBatchSize(size uint) ApplicationBuilder
+	Now() (Application, error)
+}
+
+// IndexFn returns the index keys of the data of a content
+type IndexFn func(data []byte) [][]byte
+
+// Application represents a file application
+type Application interface {
+	databases.Application
+
+	// RotateKey re-encrypts, batch by batch, the contents and reference of the database encrypted using the old key
+	// with the new key.  Every batch only appends its contents and replaces the reference, so an interrupted rotation
+	// can be resumed by calling it again
+	RotateKey(name string, oldKeyID uint, newKeyID uint) error
+
+	// TrainDictionary trains a new compression dictionary on samples of the committed contents of the kind and stores it
+	// in the database.  The new contents of the kind are then compressed using the latest dictionary of their kind.
+	// The dictionary is stored without a commit, therefore the commit callbacks are not executed
+	TrainDictionary(context uint, kind uint) error
+
+	// LookupIndex returns the hashes of the committed contents of the kind whose data contains the key in the index
+	LookupIndex(context uint, kind uint, indexName string, key []byte) ([]hash.Hash, error)
+
+	// Watch delivers the commits written to the database by any process as they are detected, using inotify on Linux
+	// when the database is on the OS filesystem, and polling at the interval otherwise.  The contexts opened on the
+	// database are refreshed before their next read once a commit is detected
+	Watch(name string, interval time.Duration) (<-chan databases.CommitEvent, databases.CancelFn, error)
+
+	// Migrate upgrades, in place, the database from a previous format version to the current one.  The database is
+	// written to a destination then swapped, so an interrupted migration leaves either format in place
+	Migrate(name string) error
+
+	// Refresh reads the reference of the database of the context again, so that it sees the commits of other processes
+	Refresh(context uint) error
+
+	// CacheStats returns the statistics of the read cache, empty when there is no read cache
+	CacheStats() CacheStats
+
+	// OpenMapped opens a read-only context on the database whose reads are served, without copy, as slices of a
+	// memory mapping of its file.  The file is mapped again when the context is refreshed, the previous mappings
+	// being kept until the context is closed.  The returned data must not be modified, and must not be used once the
+	// context is closed
+	OpenMapped(name string) (*uint, error)
+}
+
+// CacheStats represents the statistics of the read cache, its length and size being its amount and bytes of contents
+type CacheStats struct {
+	Hits      uint
+	Misses    uint
+	Evictions uint
+	Length    uint
+	Size      uint
+	Capacity  uint
+}
+
+// KeyProvider represents the provider of the keys used to encrypt the contents and the reference
+type KeyProvider interface {
+	Current() (uint, error)
+	Fetch(id uint) ([]byte, error)
+}
+
+// Storage represents the storage the databases are persisted on
+type Storage interface {
+	Exists(path string) (bool, error)
+	Create(path string) error
+	Open(path string) (StorageFile, error)
+	Remove(path string) error
+	Rename(from string, to string) error
+	// SyncDir makes the creations, removals and renames of the files of the directory durable.  They may be lost
+	// on a crash until then
+	SyncDir(path string) error
+	// Lock acquires the lock on the given sidecar path, creating its file when missing.  The sidecar
+	// is never renamed nor removed, so the lock remains exclusive while the database is rewritten
+	Lock(path string) (StorageLock, error)
+}
+
+// StorageFile represents an opened file of a storage
+type StorageFile interface {
+	ReadAt(data []byte, offset int64) (int, error)
+	WriteAt(data []byte, offset int64) (int, error)
+	Size() (int64, error)
+	Sync() error
+	Close() error
+}
+
+// StorageLock represents an acquired lock on a storage path
+type StorageLock interface {
+	Unlock() error
 }
@@ -0,0 +1,215 @@
+package files
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/applications/conformance"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+func TestApplication_withSigner_Conformance(t *testing.T) {
+	dirPath := "./test_files"
+	signer := references.NewSignerForTests("this is a seed")
+	conformance.Execute(t, func() (databases.Application, func()) {
+		app := newApplicationForTests(t, dirPath, withSignaturesForTests(signer, []ed25519.PublicKey{
+			signer.Public().(ed25519.PublicKey),
+		}))
+
+		return app, func() {
+			os.RemoveAll(dirPath)
+		}
+	})
+}
+
+func TestApplication_withSigner_thenOpenWithTrustedAuthors_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	signer := references.NewSignerForTests("this is a seed")
+	other := references.NewSignerForTests("this is another seed")
+	list := []contents.Content{
+		contents.NewContentForTests(0, []byte("this is some data")),
+	}
+
+	app := newApplicationForTests(t, dirPath, withSignaturesForTests(signer, nil))
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, list) {
+		return
+	}
+
+	// the commits are signed by the author:
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	app.Close(*pContext)
+	commit := reference.Commits().Latest()
+	if !commit.HasSignature() {
+		t.Errorf("the commit was expected to be signed")
+		return
+	}
+
+	if !bytes.Equal(commit.Signature().Author(), signer.Public().(ed25519.PublicKey)) {
+		t.Errorf("the commit was expected to be signed by the signer")
+		return
+	}
+
+	// trusting the author:
+	trustedApp := newApplicationForTests(t, dirPath, withSignaturesForTests(nil, []ed25519.PublicKey{
+		signer.Public().(ed25519.PublicKey),
+	}))
+
+	if trustedApp == nil {
+		return
+	}
+
+	if !retrieveAllForTests(t, trustedApp, name, list) {
+		return
+	}
+
+	// trusting another author:
+	untrustedApp := newApplicationForTests(t, dirPath, withSignaturesForTests(nil, []ed25519.PublicKey{
+		other.Public().(ed25519.PublicKey),
+	}))
+
+	if untrustedApp == nil {
+		return
+	}
+
+	_, err = untrustedApp.Open(name)
+	if !errors.Is(err, ErrUntrustedCommit) {
+		t.Errorf("the error was expected to be an untrusted commit error, %v returned", err)
+		return
+	}
+
+	// tampering the signature:
+	path := filepath.Join(dirPath, name)
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	index := bytes.Index(fileBytes, commit.Signature().Data())
+	fileBytes[index]++
+	err = os.WriteFile(path, fileBytes, filePermission)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = trustedApp.Open(name)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func TestApplication_requireSignedCommits_withUnsignedCommit_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitForTests(t, app, name, []contents.Content{
+		contents.NewContentForTests(0, []byte("this is some data")),
+	}) {
+		return
+	}
+
+	requiredApp := newApplicationForTests(t, dirPath, withSignaturesForTests(nil, nil))
+	if requiredApp == nil {
+		return
+	}
+
+	_, err = requiredApp.Open(name)
+	if !errors.Is(err, ErrUnsignedCommit) {
+		t.Errorf("the error was expected to be an unsigned commit error, %v returned", err)
+		return
+	}
+
+	// an unsigned commit cannot be written either:
+	otherName := "my_other_name"
+	err = requiredApp.New(otherName)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := requiredApp.Open(otherName)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer requiredApp.Close(*pContext)
+	err = requiredApp.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = requiredApp.Insert(*pContext, contents.NewContentForTests(0, []byte("this is some data")))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = requiredApp.Commit(*pContext)
+	if !errors.Is(err, ErrUnsignedCommit) {
+		t.Errorf("the error was expected to be an unsigned commit error, %v returned", err)
+		return
+	}
+}
+
+func withSignaturesForTests(signer ed25519.PrivateKey, trustedAuthors []ed25519.PublicKey) func(builder ApplicationBuilder) {
+	return func(builder ApplicationBuilder) {
+		builder.RequireSignedCommits()
+		if signer != nil {
+			builder.WithSigner(signer)
+		}
+
+		if trustedAuthors != nil {
+			builder.WithTrustedAuthors(trustedAuthors)
+		}
+	}
+}
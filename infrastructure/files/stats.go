@@ -0,0 +1,111 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+// Stats returns the statistics of the database of the context, derived from its reference and the pointers of its
+// content keys.  The contents pinned by a tag are live, even once deleted.  A context opened on a tag counts the
+// contents of the tag as live
+func (app *application) Stats(context uint) (*databases.Stats, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		size, err := pContext.conn.Size()
+		if err != nil {
+			return nil, err
+		}
+
+		stats := databases.Stats{
+			FileSize:        uint(size),
+			HeaderSize:      pContext.dataOffset,
+			ContentsPerKind: map[uint]uint{},
+		}
+
+		stats.DataSize = stats.FileSize - stats.HeaderSize
+		if pContext.reference == nil {
+			return &stats, nil
+		}
+
+		stats.Commits = uint(len(pContext.reference.Commits().List()))
+		if pContext.reference.HasContentKeys() {
+			for _, oneContentKey := range pContext.reference.ContentKeys().List() {
+				stats.ContentsPerKind[oneContentKey.Kind()]++
+			}
+		}
+
+		liveBytes, err := app.liveBytes(pContext.reference)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.LiveBytes = liveBytes
+
+		amount := uint(0)
+		for _, oneAmount := range stats.ContentsPerKind {
+			amount += oneAmount
+		}
+
+		if stats.LiveBytes > stats.DataSize {
+			str := fmt.Sprintf("the contents of the database (name: %s) were expected to use at most %d bytes, %d used", pContext.name, stats.DataSize, stats.LiveBytes)
+			return nil, errors.New(str)
+		}
+
+		stats.DeadBytes = stats.DataSize - stats.LiveBytes
+		if amount > 0 {
+			stats.AverageContentSize = float64(stats.LiveBytes) / float64(amount)
+		}
+
+		if stats.DataSize > 0 {
+			stats.Fragmentation = float64(stats.DeadBytes) / float64(stats.DataSize)
+		}
+
+		return &stats, nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot return its Stats", context)
+	return nil, errors.New(str)
+}
+
+// liveBytes returns the amount of bytes used by the content keys of the reference and the ones pinned by its tags.  A
+// pointer shared by many content keys is counted once.  The tags of a commit more recent than the latest commit of the
+// reference, such as the ones of a context opened on a tag, are skipped
+func (app *application) liveBytes(reference references.Reference) (uint, error) {
+	contentKeysList := []references.ContentKey{}
+	if reference.HasContentKeys() {
+		contentKeysList = append(contentKeysList, reference.ContentKeys().List()...)
+	}
+
+	if reference.HasTags() {
+		commitIndexes := createCommitIndexes(reference)
+		for _, oneTag := range reference.Tags().List() {
+			if _, ok := commitIndexes[oneTag.Commit().String()]; !ok {
+				continue
+			}
+
+			tagged, err := app.tagContentKeys(reference, oneTag.Commit())
+			if err != nil {
+				return 0, err
+			}
+
+			contentKeysList = append(contentKeysList, tagged...)
+		}
+	}
+
+	output := uint(0)
+	counted := map[uint]bool{}
+	for _, oneContentKey := range contentKeysList {
+		pointer := oneContentKey.Content()
+		if counted[pointer.From()] {
+			continue
+		}
+
+		counted[pointer.From()] = true
+		output += pointer.Length()
+	}
+
+	return output, nil
+}
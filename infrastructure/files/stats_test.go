@@ -0,0 +1,213 @@
+package files
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestStats_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	stats, err := app.Stats(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if stats.FileSize != 0 || stats.Commits != 0 || len(stats.ContentsPerKind) != 0 {
+		t.Errorf("the stats of an empty database were expected to be empty")
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	third := contents.NewContentForTests(1, []byte("this is the third data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first, second, third}) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	firstContentKey, err := reference.ContentKeys().Fetch(first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	stats, err = app.Stats(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if stats.HeaderSize+stats.DataSize != stats.FileSize || stats.HeaderSize <= 0 {
+		t.Errorf("the header size (%d) and data size (%d) were expected to sum up to the file size (%d)", stats.HeaderSize, stats.DataSize, stats.FileSize)
+		return
+	}
+
+	if stats.Commits != 1 || !reflect.DeepEqual(stats.ContentsPerKind, map[uint]uint{0: 2, 1: 1}) {
+		t.Errorf("the amount of commits or contents per kind are invalid")
+		return
+	}
+
+	if stats.LiveBytes != stats.DataSize || stats.DeadBytes != 0 || stats.Fragmentation != 0 {
+		t.Errorf("the data region was expected to NOT contain dead bytes")
+		return
+	}
+
+	if stats.AverageContentSize != float64(stats.LiveBytes)/3 {
+		t.Errorf("the average content size was expected to be %f, %f returned", float64(stats.LiveBytes)/3, stats.AverageContentSize)
+		return
+	}
+
+	// the bytes of a deleted content are dead:
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	stats, err = app.Stats(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if stats.Commits != 2 || stats.ContentsPerKind[0] != 1 {
+		t.Errorf("the amount of commits or contents per kind are invalid")
+		return
+	}
+
+	if stats.DeadBytes != firstContentKey.Content().Length() {
+		t.Errorf("%d dead bytes were expected, %d returned", firstContentKey.Content().Length(), stats.DeadBytes)
+		return
+	}
+
+	expected := float64(stats.DeadBytes) / float64(stats.DataSize)
+	if stats.Fragmentation != expected {
+		t.Errorf("the fragmentation was expected to be %f, %f returned", expected, stats.Fragmentation)
+		return
+	}
+}
+
+func TestStats_withTaggedThenErased_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	pContext := openThenLockForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first, second}) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Tag(*pContext, "v1", reference.Commits().Latest().Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the bytes of a deleted content pinned by a tag are live:
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	stats, err := app.Stats(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if stats.Commits != 2 || stats.ContentsPerKind[0] != 1 {
+		t.Errorf("the amount of commits or contents per kind are invalid")
+		return
+	}
+
+	if stats.LiveBytes != stats.DataSize || stats.DeadBytes != 0 || stats.Fragmentation != 0 {
+		t.Errorf("the data region was expected to NOT contain dead bytes, %d returned", stats.DeadBytes)
+		return
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the context opened on the tag counts the same live bytes:
+	pTagContext, err := app.OpenTag(name, "v1")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pTagContext)
+	tagStats, err := app.Stats(*pTagContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if tagStats.Commits != 1 || tagStats.ContentsPerKind[0] != 2 {
+		t.Errorf("the amount of commits or contents per kind of the tagged context are invalid")
+		return
+	}
+
+	if tagStats.LiveBytes != stats.LiveBytes || tagStats.DeadBytes != 0 {
+		t.Errorf("the tagged context was expected to contain %d live bytes, %d returned", stats.LiveBytes, tagStats.LiveBytes)
+		return
+	}
+}
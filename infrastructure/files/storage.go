@@ -0,0 +1,96 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/fslock"
+)
+
+type osStorage struct {
+}
+
+func createOSStorage() Storage {
+	out := osStorage{}
+	return &out
+}
+
+// Exists returns true if the path is an existing file, false otherwise
+func (app *osStorage) Exists(path string) (bool, error) {
+	fileInfo, err := os.Stat(path)
+	if err == nil {
+		return !fileInfo.IsDir(), nil
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// Create creates an empty file, and its parent directories if needed
+func (app *osStorage) Create(path string) error {
+	dirPath := filepath.Dir(path)
+	if _, err := os.Stat(dirPath); errors.Is(err, os.ErrNotExist) {
+		err := os.MkdirAll(dirPath, filePermission)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// Open opens an existing file for reading and writing
+func (app *osStorage) Open(path string) (StorageFile, error) {
+	pFile, err := os.OpenFile(path, os.O_RDWR, filePermission)
+	if err != nil {
+		return nil, err
+	}
+
+	return createOSStorageFile(pFile), nil
+}
+
+// Remove removes an existing file
+func (app *osStorage) Remove(path string) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.IsDir() {
+		str := fmt.Sprintf("the path (%s) was expected to be a file, not a directory", path)
+		return errors.New(str)
+	}
+
+	return os.Remove(path)
+}
+
+// Rename renames a file
+func (app *osStorage) Rename(from string, to string) error {
+	return os.Rename(from, to)
+}
+
+// SyncDir makes the creations, removals and renames of the files of the directory durable
+func (app *osStorage) SyncDir(path string) error {
+	return syncDir(path)
+}
+
+// Lock acquires the lock on a file, or returns an error if it is already locked
+func (app *osStorage) Lock(path string) (StorageLock, error) {
+	pLock := fslock.New(path)
+	err := pLock.TryLock()
+	if err != nil {
+		return nil, err
+	}
+
+	return pLock, nil
+}
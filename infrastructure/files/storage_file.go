@@ -0,0 +1,52 @@
+package files
+
+import "os"
+
+type osStorageFile struct {
+	pFile *os.File
+}
+
+func createOSStorageFile(
+	pFile *os.File,
+) StorageFile {
+	out := osStorageFile{
+		pFile: pFile,
+	}
+
+	return &out
+}
+
+// ReadAt reads data at offset
+func (obj *osStorageFile) ReadAt(data []byte, offset int64) (int, error) {
+	return obj.pFile.ReadAt(data, offset)
+}
+
+// WriteAt writes data at offset
+func (obj *osStorageFile) WriteAt(data []byte, offset int64) (int, error) {
+	return obj.pFile.WriteAt(data, offset)
+}
+
+// Size returns the size of the file
+func (obj *osStorageFile) Size() (int64, error) {
+	fileInfo, err := obj.pFile.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	return fileInfo.Size(), nil
+}
+
+// Sync commits the written data to the disk
+func (obj *osStorageFile) Sync() error {
+	return obj.pFile.Sync()
+}
+
+// Close closes the file
+func (obj *osStorageFile) Close() error {
+	return obj.pFile.Close()
+}
+
+// Map maps the given amount of bytes of the file in memory, read-only
+func (obj *osStorageFile) Map(size int64) ([]byte, error) {
+	return mapFile(obj.pFile, size)
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package files
+
+// syncDir does nothing, since the directories cannot be synced on this platform
+func syncDir(path string) error {
+	return nil
+}
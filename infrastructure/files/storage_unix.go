@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package files
+
+import "os"
+
+// syncDir flushes the entries of the directory to the disk
+func syncDir(path string) error {
+	pDir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	err = pDir.Sync()
+	closeErr := pDir.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
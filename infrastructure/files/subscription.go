@@ -0,0 +1,333 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+const deletesResourceKeyword = "deletes"
+
+type subscription struct {
+	name    string
+	events  chan databases.CommitEvent
+	signal  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	mutex   sync.Mutex
+	pending []databases.CommitEvent
+}
+
+func createSubscription(name string, pending []databases.CommitEvent) *subscription {
+	out := subscription{
+		name:    name,
+		events:  make(chan databases.CommitEvent),
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		pending: pending,
+	}
+
+	return &out
+}
+
+// publish queues the event, so that a slow subscriber never blocks the commits
+func (obj *subscription) publish(event databases.CommitEvent) {
+	obj.mutex.Lock()
+	obj.pending = append(obj.pending, event)
+	obj.mutex.Unlock()
+
+	select {
+	case obj.signal <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued events, in order, until the subscription is cancelled
+func (obj *subscription) run() {
+	defer close(obj.events)
+	for {
+		obj.mutex.Lock()
+		pending := obj.pending
+		obj.pending = nil
+		obj.mutex.Unlock()
+
+		for _, oneEvent := range pending {
+			select {
+			case obj.events <- oneEvent:
+			case <-obj.done:
+				return
+			}
+		}
+
+		select {
+		case <-obj.signal:
+		case <-obj.done:
+			return
+		}
+	}
+}
+
+func (obj *subscription) cancel() {
+	obj.once.Do(func() {
+		close(obj.done)
+	})
+}
+
+// Subscribe delivers the commits of the database made using this application, as they are made.  When the commit
+// to resume from is provided, the commits made after it are delivered first
+func (app *application) Subscribe(name string, from hash.Hash) (<-chan databases.CommitEvent, databases.CancelFn, error) {
+	exists, err := app.Exists(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		str := fmt.Sprintf("the database (name: %s) does not exists and therefore cannot be subscribed to", name)
+		return nil, nil, errors.New(str)
+	}
+
+	pending := []databases.CommitEvent{}
+	if from != nil {
+		reference, err := app.readDatabaseReference(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pending, err = app.replayCommits(name, reference, from)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pSubscription := createSubscription(name, pending)
+	app.subscriptionsMutex.Lock()
+	identifier := app.nextSubscription
+	app.subscriptions[identifier] = pSubscription
+	app.nextSubscription++
+	app.subscriptionsMutex.Unlock()
+
+	go pSubscription.run()
+	return pSubscription.events, func() {
+		app.subscriptionsMutex.Lock()
+		delete(app.subscriptions, identifier)
+		app.subscriptionsMutex.Unlock()
+		pSubscription.cancel()
+	}, nil
+}
+
+// publishCommit delivers the commit, once written, to the subscribers of its database
+func (app *application) publishCommit(pContext *context, reference references.Reference, commit references.Commit) {
+	app.subscriptionsMutex.Lock()
+	defer app.subscriptionsMutex.Unlock()
+	if len(app.subscriptions) <= 0 {
+		return
+	}
+
+	inserted := []references.ContentKey{}
+	if reference.HasContentKeys() {
+		for _, oneContentKey := range reference.ContentKeys().List() {
+			if oneContentKey.Commit().Compare(commit.Hash()) {
+				inserted = append(inserted, oneContentKey)
+			}
+		}
+	}
+
+	deleted := []references.ContentKey{}
+	for _, oneContentKey := range pContext.delList {
+		deleted = append(deleted, oneContentKey)
+	}
+
+	event := createCommitEvent(pContext.name, commit, inserted, deleted)
+	for _, oneSubscription := range app.subscriptions {
+		if oneSubscription.name == pContext.name {
+			oneSubscription.publish(event)
+		}
+	}
+}
+
+// replayCommits returns the events of the commits made after the commit to resume from, or of every commit when
+// there is none.  The contents deleted since their insertion are retrieved from the deletes resources of the reference
+func (app *application) replayCommits(name string, reference references.Reference, from hash.Hash) ([]databases.CommitEvent, error) {
+	commits := reference.Commits().List()
+	index := -1
+	for oneIndex, oneCommit := range commits {
+		if from != nil && oneCommit.Hash().Compare(from) {
+			index = oneIndex
+			break
+		}
+	}
+
+	if from != nil && index < 0 {
+		str := fmt.Sprintf("the commit (hash: %s) does not exists in the database (name: %s) and therefore cannot be resumed from", from.String(), name)
+		return nil, errors.New(str)
+	}
+
+	inserted := map[string][]references.ContentKey{}
+	if reference.HasContentKeys() {
+		for _, oneContentKey := range reference.ContentKeys().List() {
+			keyname := oneContentKey.Commit().String()
+			inserted[keyname] = append(inserted[keyname], oneContentKey)
+		}
+	}
+
+	deleted := map[string][]references.ContentKey{}
+	if reference.HasResources() {
+		for _, oneResource := range reference.Resources().List() {
+			if !strings.HasPrefix(oneResource.Name(), createDeletesPrefix()) {
+				continue
+			}
+
+			contentKeys, err := app.referenceContentKeysAdapter.ToContentKeys(oneResource.Data())
+			if err != nil {
+				return nil, err
+			}
+
+			deletedBy := strings.TrimPrefix(oneResource.Name(), createDeletesPrefix())
+			deleted[deletedBy] = contentKeys.List()
+			for _, oneContentKey := range contentKeys.List() {
+				keyname := oneContentKey.Commit().String()
+				inserted[keyname] = append(inserted[keyname], oneContentKey)
+			}
+		}
+	}
+
+	output := []databases.CommitEvent{}
+	for _, oneCommit := range commits[index+1:] {
+		keyname := oneCommit.Hash().String()
+		output = append(output, createCommitEvent(name, oneCommit, inserted[keyname], deleted[keyname]))
+	}
+
+	return output, nil
+}
+
+// recordDeletes adds the content keys deleted by the commit to the resources, so that the commit can be replayed
+// once they are removed from the reference.  Returns nil when there is no resource
+func (app *application) recordDeletes(pContext *context, commit references.Commit, resources references.Resources) (references.Resources, error) {
+	if len(pContext.delList) <= 0 {
+		return resources, nil
+	}
+
+	keynames := []string{}
+	for oneKeyname := range pContext.delList {
+		keynames = append(keynames, oneKeyname)
+	}
+
+	sort.Strings(keynames)
+	deletedList := []references.ContentKey{}
+	for _, oneKeyname := range keynames {
+		deletedList = append(deletedList, pContext.delList[oneKeyname])
+	}
+
+	deleted, err := app.referenceContentKeysBuilder.Create().
+		WithList(deletedList).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := app.referenceContentKeysAdapter.ToContent(deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := app.referenceResourceBuilder.Create().
+		WithName(createDeletesName(commit.Hash())).
+		WithData(data).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resourcesList := []references.Resource{}
+	if resources != nil {
+		resourcesList = append(resourcesList, resources.List()...)
+	}
+
+	return app.referenceResourcesBuilder.Create().
+		WithList(append(resourcesList, resource)).
+		Now()
+}
+
+// readDatabaseReference reads the reference of the database without opening a context on it.  Returns an error
+// when the database does not contain any commit
+func (app *application) readDatabaseReference(name string) (references.Reference, error) {
+	err := app.recover(name)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := app.loadDatabaseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if reference == nil {
+		str := fmt.Sprintf("the database (name: %s) does not contain any commit", name)
+		return nil, errors.New(str)
+	}
+
+	return reference, nil
+}
+
+// loadDatabaseReference reads the reference of the database, as it is on the storage.  Returns nil when the database
+// does not contain any commit
+func (app *application) loadDatabaseReference(name string) (references.Reference, error) {
+	conn, err := app.storage.Open(filepath.Join(app.dirPath, name))
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+	pContext := &context{
+		conn: conn,
+		name: name,
+	}
+
+	err = app.readReference(pContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return pContext.reference, nil
+}
+
+// createCommitEvent creates the event of the commit, its content keys sorted by kind, then by hash
+func createCommitEvent(name string, commit references.Commit, inserted []references.ContentKey, deleted []references.ContentKey) databases.CommitEvent {
+	return databases.CommitEvent{
+		Name:     name,
+		Commit:   commit,
+		Inserted: sortContentKeys(inserted),
+		Deleted:  sortContentKeys(deleted),
+	}
+}
+
+func sortContentKeys(list []references.ContentKey) []references.ContentKey {
+	output := append([]references.ContentKey{}, list...)
+	sort.Slice(output, func(i int, j int) bool {
+		if output[i].Kind() != output[j].Kind() {
+			return output[i].Kind() < output[j].Kind()
+		}
+
+		return bytes.Compare(output[i].Hash(), output[j].Hash()) < 0
+	})
+
+	return output
+}
+
+func createDeletesName(commit hash.Hash) string {
+	return fmt.Sprintf("%s%s", createDeletesPrefix(), commit.String())
+}
+
+func createDeletesPrefix() string {
+	return fmt.Sprintf("%s%s", deletesResourceKeyword, fileNameExtensionDelimiter)
+}
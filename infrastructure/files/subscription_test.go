@@ -0,0 +1,163 @@
+package files
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/domain/references"
+)
+
+func TestSubscribe_thenResume_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	_, _, err := app.Subscribe("my_name", nil)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	pContext := openThenLockForTests(t, app, "my_name")
+	if pContext == nil {
+		return
+	}
+
+	defer app.Close(*pContext)
+	events, cancelFn, err := app.Subscribe("my_name", nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	third := contents.NewContentForTests(1, []byte("this is the third data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first}) {
+		return
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{second}) {
+		return
+	}
+
+	err = app.Erase(*pContext, second.Kind(), second.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{third}) {
+		return
+	}
+
+	live := receiveEventsForTests(t, events, 3)
+	if live == nil {
+		return
+	}
+
+	if !verifyEventForTests(t, live[1], []contents.Content{second}, []contents.Content{}) {
+		return
+	}
+
+	if !verifyEventForTests(t, live[2], []contents.Content{third}, []contents.Content{second}) {
+		return
+	}
+
+	// once cancelled, the channel is closed:
+	cancelFn()
+	for range events {
+	}
+
+	// resume from the first commit, the deleted content is still delivered as inserted by its commit:
+	resumed, cancelFn, err := app.Subscribe("my_name", live[0].Commit.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer cancelFn()
+	replayed := receiveEventsForTests(t, resumed, 2)
+	if replayed == nil {
+		return
+	}
+
+	for index, oneEvent := range replayed {
+		if !oneEvent.Commit.Hash().Compare(live[index+1].Commit.Hash()) {
+			t.Errorf("the replayed event (index: %d) was expected to contain the commit (hash: %s)", index, live[index+1].Commit.Hash().String())
+			return
+		}
+	}
+
+	if !verifyEventForTests(t, replayed[0], []contents.Content{second}, []contents.Content{}) {
+		return
+	}
+
+	if !verifyEventForTests(t, replayed[1], []contents.Content{third}, []contents.Content{second}) {
+		return
+	}
+
+	_, _, err = app.Subscribe("my_name", contents.NewContentForTests(0, []byte("this is not a commit")).Hash())
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
+
+func receiveEventsForTests(t *testing.T, events <-chan databases.CommitEvent, amount int) []databases.CommitEvent {
+	output := []databases.CommitEvent{}
+	for len(output) < amount {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Errorf("%d events were expected, the channel was closed after %d", amount, len(output))
+				return nil
+			}
+
+			output = append(output, event)
+		case <-time.After(5 * time.Second):
+			t.Errorf("%d events were expected, %d received before the timeout", amount, len(output))
+			return nil
+		}
+	}
+
+	return output
+}
+
+func verifyEventForTests(t *testing.T, event databases.CommitEvent, inserted []contents.Content, deleted []contents.Content) bool {
+	expected := map[string][]contents.Content{
+		"inserted": inserted,
+		"deleted":  deleted,
+	}
+
+	returned := map[string][]references.ContentKey{
+		"inserted": event.Inserted,
+		"deleted":  event.Deleted,
+	}
+
+	for name, list := range expected {
+		if len(returned[name]) != len(list) {
+			t.Errorf("%d %s content keys were expected, %d returned", len(list), name, len(returned[name]))
+			return false
+		}
+
+		for index, oneContent := range list {
+			contentKey := returned[name][index]
+			if contentKey.Kind() != oneContent.Kind() || !contentKey.Hash().Compare(oneContent.Hash()) {
+				t.Errorf("the %s content key (index: %d) was expected to be the content (hash: %s)", name, index, oneContent.Hash().String())
+				return false
+			}
+		}
+	}
+
+	return true
+}
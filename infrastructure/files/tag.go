@@ -0,0 +1,284 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steve-care-software/databases/domain/references"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+// Tag pins the commit under an immutable name.  Only the name and commit are stored, the contentKeys of the database at
+// that commit being restored when the tag is opened
+func (app *application) Tag(context uint, name string, commit hash.Hash) error {
+	if pContext, ok := app.contexts[context]; ok {
+		err := app.verifyWritable(pContext)
+		if err != nil {
+			return err
+		}
+
+		if pContext.reference == nil {
+			str := fmt.Sprintf("the database (name: %s) does not contain any commit yet and therefore cannot be tagged", pContext.name)
+			return errors.New(str)
+		}
+
+		tagsList := []references.Tag{}
+		if pContext.reference.HasTags() {
+			tagsList = append(tagsList, pContext.reference.Tags().List()...)
+			if _, err := pContext.reference.Tags().Fetch(name); err == nil {
+				str := fmt.Sprintf("the tag (name: %s) already exists and therefore cannot be created again", name)
+				return errors.New(str)
+			}
+		}
+
+		// the contents of the commit are restored from the deletes recorded by the following commits, which must exist:
+		_, err = app.tagContentKeys(pContext.reference, commit)
+		if err != nil {
+			return err
+		}
+
+		tag, err := app.referenceTagBuilder.Create().
+			WithName(name).
+			WithCommit(commit).
+			Now()
+
+		if err != nil {
+			return err
+		}
+
+		tags, err := app.referenceTagsBuilder.Create().
+			WithList(append(tagsList, tag)).
+			Now()
+
+		if err != nil {
+			return err
+		}
+
+		builder := app.referenceBuilder.Create().
+			WithCommits(pContext.reference.Commits()).
+			WithTags(tags)
+
+		if pContext.reference.HasContentKeys() {
+			builder.WithContentKeys(pContext.reference.ContentKeys())
+		}
+
+		if pContext.reference.HasResources() {
+			builder.WithResources(pContext.reference.Resources())
+		}
+
+		if pContext.reference.HasKinds() {
+			builder.WithKinds(pContext.reference.Kinds())
+		}
+
+		reference, err := builder.Now()
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err := app.referenceAdapter.ToContent(reference)
+		if err != nil {
+			return err
+		}
+
+		referenceBytes, err = app.encryptReference(referenceBytes)
+		if err != nil {
+			return err
+		}
+
+		return app.rewrite(pContext, referenceBytes, []byte{})
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot Tag using this context", context)
+	return errors.New(str)
+}
+
+// ResolveTag returns the hash of the commit pinned by the tag
+func (app *application) ResolveTag(context uint, name string) (*hash.Hash, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if pContext.reference == nil || !pContext.reference.HasTags() {
+			str := fmt.Sprintf("the database (name: %s) does not contain any tag and therefore cannot resolve the tag (name: %s)", pContext.name, name)
+			return nil, errors.New(str)
+		}
+
+		tag, err := pContext.reference.Tags().Fetch(name)
+		if err != nil {
+			return nil, err
+		}
+
+		commit := tag.Commit()
+		return &commit, nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot ResolveTag using this context", context)
+	return nil, errors.New(str)
+}
+
+// ListTags returns the tags of the database, in creation order
+func (app *application) ListTags(context uint) ([]references.Tag, error) {
+	if pContext, ok := app.contexts[context]; ok {
+		app.refreshWatched(pContext)
+		if pContext.reference == nil || !pContext.reference.HasTags() {
+			return []references.Tag{}, nil
+		}
+
+		return pContext.reference.Tags().List(), nil
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot ListTags using this context", context)
+	return nil, errors.New(str)
+}
+
+// tagContentKeys returns the contentKeys of the database at the commit.  The contentKeys deleted after the commit
+// are removed from the reference, therefore they are restored from the deletes recorded by the following commits
+func (app *application) tagContentKeys(reference references.Reference, commit hash.Hash) ([]references.ContentKey, error) {
+	commits := reference.Commits().List()
+	index := -1
+	for oneIndex, oneCommit := range commits {
+		if oneCommit.Hash().Compare(commit) {
+			index = oneIndex
+			break
+		}
+	}
+
+	if index < 0 {
+		str := fmt.Sprintf("the commit (hash: %s) does not exists and therefore cannot be tagged", commit.String())
+		return nil, errors.New(str)
+	}
+
+	tagged := map[string]bool{}
+	for _, oneCommit := range commits[:index+1] {
+		tagged[oneCommit.Hash().String()] = true
+	}
+
+	contentKeysList := []references.ContentKey{}
+	if reference.HasContentKeys() {
+		contentKeysList = append(contentKeysList, reference.ContentKeys().List()...)
+	}
+
+	for _, oneCommit := range commits[index+1:] {
+		if !oneCommit.Action().HasDelete() {
+			continue
+		}
+
+		deleted, err := app.fetchDeletes(reference, oneCommit.Hash())
+		if err != nil {
+			str := fmt.Sprintf("the commit (hash: %s) cannot be tagged because the contents deleted by the commit (hash: %s) were not recorded: %s", commit.String(), oneCommit.Hash().String(), err.Error())
+			return nil, errors.New(str)
+		}
+
+		contentKeysList = append(contentKeysList, deleted...)
+	}
+
+	output := []references.ContentKey{}
+	for _, oneContentKey := range contentKeysList {
+		if _, ok := tagged[oneContentKey.Commit().String()]; ok {
+			output = append(output, oneContentKey)
+		}
+	}
+
+	// the restored contentKeys are listed in the order of the commits that inserted them, as the current ones are:
+	commitIndexes := createCommitIndexes(reference)
+	sort.SliceStable(output, func(i int, j int) bool {
+		return commitIndexes[output[i].Commit().String()] < commitIndexes[output[j].Commit().String()]
+	})
+
+	return output, nil
+}
+
+// fetchDeletes returns the contentKeys deleted by the commit, as recorded in the resources of the reference
+func (app *application) fetchDeletes(reference references.Reference, commit hash.Hash) ([]references.ContentKey, error) {
+	if !reference.HasResources() {
+		str := fmt.Sprintf("the reference does not contain any resource and therefore cannot contain the deletes of the commit (hash: %s)", commit.String())
+		return nil, errors.New(str)
+	}
+
+	resource, err := reference.Resources().Fetch(createDeletesName(commit))
+	if err != nil {
+		return nil, err
+	}
+
+	contentKeys, err := app.referenceContentKeysAdapter.ToContentKeys(resource.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	return contentKeys.List(), nil
+}
+
+// tagReference returns the reference as it was at the commit pinned by the tag, its contentKeys being restored from the
+// current contentKeys and the recorded deletes
+func (app *application) tagReference(reference references.Reference, name string) (references.Reference, error) {
+	if !reference.HasTags() {
+		str := fmt.Sprintf("the database does not contain any tag and therefore cannot be opened on the tag (name: %s)", name)
+		return nil, errors.New(str)
+	}
+
+	tag, err := reference.Tags().Fetch(name)
+	if err != nil {
+		return nil, err
+	}
+
+	commitsList := []references.Commit{}
+	for _, oneCommit := range reference.Commits().List() {
+		commitsList = append(commitsList, oneCommit)
+		if oneCommit.Hash().Compare(tag.Commit()) {
+			break
+		}
+	}
+
+	commits, err := app.referenceCommitsBuilder.Create().
+		WithList(commitsList).
+		Now()
+
+	if err != nil {
+		return nil, err
+	}
+
+	contentKeysList, err := app.tagContentKeys(reference, tag.Commit())
+	if err != nil {
+		return nil, err
+	}
+
+	builder := app.referenceBuilder.Create().
+		WithCommits(commits).
+		WithTags(reference.Tags())
+
+	if len(contentKeysList) > 0 {
+		contentKeys, err := app.referenceContentKeysBuilder.Create().
+			WithList(contentKeysList).
+			Now()
+
+		if err != nil {
+			return nil, err
+		}
+
+		builder.WithContentKeys(contentKeys)
+	}
+
+	if reference.HasResources() {
+		builder.WithResources(reference.Resources())
+	}
+
+	if reference.HasKinds() {
+		builder.WithKinds(reference.Kinds())
+	}
+
+	return builder.Now()
+}
+
+// verifyWritable returns an error when the context is opened on a tag or on a memory mapping
+func (app *application) verifyWritable(pContext *context) error {
+	if pContext.isMapped {
+		str := fmt.Sprintf("the context (%d) is opened on a memory mapping of the database (name: %s) and is therefore read-only", pContext.identifier, pContext.name)
+		return errors.New(str)
+	}
+
+	if pContext.pTag == nil {
+		return nil
+	}
+
+	str := fmt.Sprintf("the context (%d) is opened on the tag (name: %s) of the database (name: %s) and is therefore read-only", pContext.identifier, *pContext.pTag, pContext.name)
+	return errors.New(str)
+}
@@ -0,0 +1,243 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+func TestTag_thenOpenTag_Success(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	name := "my_name"
+	pContext := openThenLockForTests(t, app, name)
+	if pContext == nil {
+		return
+	}
+
+	first := contents.NewContentForTests(0, []byte("this is the first data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{first}) {
+		return
+	}
+
+	reference, err := app.Reference(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	firstCommit := reference.Commits().Latest().Hash()
+	err = app.Tag(*pContext, "v1", firstCommit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// tags are immutable:
+	err = app.Tag(*pContext, "v1", firstCommit)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	second := contents.NewContentForTests(0, []byte("this is the second data"))
+	if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{second}) {
+		return
+	}
+
+	pResolved, err := app.ResolveTag(*pContext, "v1")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !pResolved.Compare(firstCommit) {
+		t.Errorf("the tag was expected to resolve to the commit (hash: %s), %s returned", firstCommit.String(), pResolved.String())
+		return
+	}
+
+	// a commit can be tagged once contents were deleted after it, the deleted contents are pinned by the tag:
+	err = app.Erase(*pContext, first.Kind(), first.Hash())
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = app.Tag(*pContext, "v2", firstCommit)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	tags, err := app.ListTags(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if len(tags) != 2 || tags[0].Name() != "v1" || tags[1].Name() != "v2" {
+		t.Errorf("the database was expected to contain the v1 and v2 tags")
+		return
+	}
+
+	err = app.Close(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// the tagged contexts read the database as it was at the tagged commit:
+	for _, oneTag := range []string{"v1", "v2"} {
+		pTagContext, err := app.OpenTag(name, oneTag)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !retrieveOnContextForTests(t, app, *pTagContext, []contents.Content{first}) {
+			return
+		}
+
+		_, err = app.Retrieve(*pTagContext, second.Kind(), second.Hash())
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+
+		tagReference, err := app.Reference(*pTagContext)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		if !tagReference.Commits().Latest().Hash().Compare(firstCommit) {
+			t.Errorf("the latest commit of the tagged context was expected to be the tagged commit")
+			return
+		}
+
+		// the tagged context is read-only:
+		err = app.Insert(*pTagContext, contents.NewContentForTests(0, []byte("this is the third data")))
+		if err == nil {
+			t.Errorf("the error was expected to be valid, nil returned")
+			return
+		}
+
+		err = app.Close(*pTagContext)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+}
+
+func TestOpenTag_thenWrite_thenCopy_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	destination := "my_destination"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	for _, oneName := range []string{name, destination} {
+		pContext := openThenLockForTests(t, app, oneName)
+		if pContext == nil {
+			return
+		}
+
+		content := contents.NewContentForTests(0, []byte(fmt.Sprintf("this is the data of %s", oneName)))
+		if !insertThenCommitOnContextForTests(t, app, *pContext, []contents.Content{content}) {
+			return
+		}
+
+		reference, err := app.Reference(*pContext)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		err = app.Tag(*pContext, "v1", reference.Commits().Latest().Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+
+		app.Close(*pContext)
+	}
+
+	pTagContext, err := app.OpenTag(name, "v1")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pTagContext)
+	err = app.Write(*pTagContext, 0, []byte("this is some data"))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = app.Copy(*pTagContext, destination)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// a mapped context is read-only as well:
+	mappedApp := newApplicationForTests(t, dirPath, nil)
+	if mappedApp == nil {
+		return
+	}
+
+	pMappedContext, err := mappedApp.OpenMapped(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer mappedApp.Close(*pMappedContext)
+	err = mappedApp.Write(*pMappedContext, 0, []byte("this is some data"))
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = mappedApp.Copy(*pMappedContext, destination)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the destination is left untouched:
+	exists, err := app.Exists(destination)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !exists {
+		t.Errorf("the destination database was expected to exist")
+		return
+	}
+}
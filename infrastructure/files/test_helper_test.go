@@ -0,0 +1,117 @@
+package files
+
+import (
+	"reflect"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+// newApplicationForTests builds an application on the OS storage of the directory, then returns nil once the error
+// is reported when it cannot be built.  The configure func, if any, adds its options to the builder
+func newApplicationForTests(t *testing.T, dirPath string, configureFn func(builder ApplicationBuilder)) Application {
+	builder := NewApplicationBuilder().Create().
+		WithStorage(NewOSStorage()).
+		WithDirPath(dirPath).
+		WithDestinationExtension("destination").
+		WithBackupExtension("backup").
+		WithReadChunkSize(10)
+
+	if configureFn != nil {
+		configureFn(builder)
+	}
+
+	app, err := builder.Now()
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	return app
+}
+
+func insertThenCommitForTests(t testing.TB, app databases.Application, name string, list []contents.Content) bool {
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return insertThenCommitOnContextForTests(t, app, *pContext, list)
+}
+
+func retrieveAllForTests(t *testing.T, app databases.Application, name string, list []contents.Content) bool {
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	defer app.Close(*pContext)
+	return retrieveOnContextForTests(t, app, *pContext, list)
+}
+
+func openThenLockForTests(t *testing.T, app databases.Application, name string) *uint {
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return nil
+	}
+
+	return pContext
+}
+
+func insertThenCommitOnContextForTests(t testing.TB, app databases.Application, context uint, list []contents.Content) bool {
+	for _, oneContent := range list {
+		err := app.Insert(context, oneContent)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return false
+		}
+	}
+
+	err := app.Commit(context)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func retrieveOnContextForTests(t *testing.T, app databases.Application, context uint, list []contents.Content) bool {
+	for _, oneContent := range list {
+		retContent, err := app.Retrieve(context, oneContent.Kind(), oneContent.Hash())
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return false
+		}
+
+		if !reflect.DeepEqual(oneContent, retContent) {
+			t.Errorf("the returned content (kind: %d, hash: %s) is invalid", oneContent.Kind(), oneContent.Hash().String())
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/libs/cryptography/hash"
+)
+
+// notifier notifies that the database file it watches may have changed
+type notifier interface {
+	Notifications() <-chan struct{}
+	Close() error
+}
+
+// Watch delivers the commits written to the database by any process, including this one, as they are detected.  The
+// database file is watched using inotify on Linux when it is on the OS filesystem, and polled at the interval otherwise.
+// The contexts of the application opened on the database are refreshed before their next read once a commit is detected
+func (app *application) Watch(name string, interval time.Duration) (<-chan databases.CommitEvent, databases.CancelFn, error) {
+	if interval <= 0 {
+		str := fmt.Sprintf("the watch interval (%s) must be greater than zero (0)", interval.String())
+		return nil, nil, errors.New(str)
+	}
+
+	exists, err := app.Exists(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		str := fmt.Sprintf("the database (name: %s) does not exists and therefore cannot be watched", name)
+		return nil, nil, errors.New(str)
+	}
+
+	reference, err := app.loadDatabaseReference(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var latest hash.Hash
+	if reference != nil {
+		latest = reference.Commits().Latest().Hash()
+	}
+
+	// the OS filesystem is watched using the notifier of the platform, if any, then polled when there is none:
+	var watchNotifier notifier
+	if _, ok := app.storage.(*osStorage); ok {
+		watchNotifier, err = createNotifier(app.dirPath, name)
+		if err != nil {
+			watchNotifier = nil
+		}
+	}
+
+	pSubscription := createSubscription(name, nil)
+	go pSubscription.run()
+	go app.watch(pSubscription, latest, interval, watchNotifier)
+	return pSubscription.events, pSubscription.cancel, nil
+}
+
+// Refresh re-opens the connection of the context then reads its reference again, so that the context sees the
+// commits written by other processes.  The pending inserts and deletes of the context are kept
+func (app *application) Refresh(context uint) error {
+	if pContext, ok := app.contexts[context]; ok {
+		changes := app.fetchChanges(pContext.name)
+
+		// the commits replace the database file, therefore the connection is opened on the current one:
+		conn, err := app.storage.Open(filepath.Join(app.dirPath, pContext.name))
+		if err != nil {
+			return err
+		}
+
+		previous := pContext.conn
+		pContext.conn = conn
+		err = app.readReference(pContext)
+		if err != nil {
+			pContext.conn = previous
+			conn.Close()
+			if pContext.isMapped {
+				app.remap(pContext)
+			}
+
+			return err
+		}
+
+		pContext.changes = changes
+		return previous.Close()
+	}
+
+	str := fmt.Sprintf("the given context (%d) does not exists and therefore cannot be refreshed", context)
+	return errors.New(str)
+}
+
+// watch publishes the commits found after the latest one every time the database may have changed, until the
+// subscription is cancelled
+func (app *application) watch(pSubscription *subscription, latest hash.Hash, interval time.Duration, watchNotifier notifier) {
+	var notifications <-chan struct{}
+	if watchNotifier != nil {
+		notifications = watchNotifier.Notifications()
+		defer watchNotifier.Close()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pSubscription.done:
+			return
+		case <-ticker.C:
+		case <-notifications:
+		}
+
+		// the database is read without recovering it, since a commit may be swapping it at the same time.  A read
+		// failing while the swap is in progress is retried on the next notification or tick:
+		reference, err := app.loadDatabaseReference(pSubscription.name)
+		if err != nil || reference == nil {
+			continue
+		}
+
+		current := reference.Commits().Latest().Hash()
+		if latest != nil && current.Compare(latest) {
+			continue
+		}
+
+		// the database was replaced when its previous latest commit is gone, therefore every commit is new:
+		events, err := app.replayCommits(pSubscription.name, reference, latest)
+		if err != nil {
+			events, err = app.replayCommits(pSubscription.name, reference, nil)
+			if err != nil {
+				continue
+			}
+		}
+
+		// the contexts are marked before the events are published, so that they see the commits of the events:
+		app.notifyChange(pSubscription.name)
+		for _, oneEvent := range events {
+			pSubscription.publish(oneEvent)
+		}
+
+		latest = current
+	}
+}
+
+// refreshWatched refreshes the context when a watcher detected a commit on its database since it was last refreshed.
+// A failed refresh is retried on the next read, the context keeping its reference meanwhile
+func (app *application) refreshWatched(pContext *context) {
+	if app.fetchChanges(pContext.name) == pContext.changes {
+		return
+	}
+
+	app.Refresh(pContext.identifier)
+}
+
+// notifyChange records that a watcher detected a commit on the database
+func (app *application) notifyChange(name string) {
+	app.changesMutex.Lock()
+	defer app.changesMutex.Unlock()
+	app.changes[name]++
+}
+
+func (app *application) fetchChanges(name string) uint {
+	app.changesMutex.Lock()
+	defer app.changesMutex.Unlock()
+	return app.changes[name]
+}
@@ -0,0 +1,147 @@
+package files
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/steve-care-software/databases/domain/contents"
+)
+
+type pollingStorageForTests struct {
+	Storage
+}
+
+func TestWatch_withCommitOfOtherApplication_refreshesContext_Success(t *testing.T) {
+	storages := map[string]struct {
+		storage  Storage
+		interval time.Duration
+	}{
+		// the interval is too long for the commit to be detected by polling:
+		"notifier": {
+			storage:  NewOSStorage(),
+			interval: time.Hour,
+		},
+		"polling": {
+			storage:  &pollingStorageForTests{NewOSStorage()},
+			interval: 10 * time.Millisecond,
+		},
+	}
+
+	for name, oneStorage := range storages {
+		t.Run(name, func(t *testing.T) {
+			dirPath := "./test_files"
+			defer func() {
+				os.RemoveAll(dirPath)
+			}()
+
+			name := "my_name"
+			app := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+				builder.WithStorage(oneStorage.storage)
+			})
+
+			if app == nil {
+				return
+			}
+
+			err := app.New(name)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			first := contents.NewContentForTests(0, []byte("this is the first data"))
+			second := contents.NewContentForTests(0, []byte("this is the second data"))
+			if !insertThenCommitForTests(t, app, name, []contents.Content{first}) {
+				return
+			}
+
+			pContext, err := app.Open(name)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			defer app.Close(*pContext)
+			events, cancelFn, err := app.Watch(name, oneStorage.interval)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			// another process commits to the database:
+			other := newApplicationForTests(t, dirPath, func(builder ApplicationBuilder) {
+				builder.WithStorage(oneStorage.storage)
+			})
+
+			if other == nil || !insertThenCommitForTests(t, other, name, []contents.Content{second}) {
+				return
+			}
+
+			select {
+			case event := <-events:
+				if len(event.Inserted) != 1 || !event.Inserted[0].Hash().Compare(second.Hash()) {
+					t.Errorf("the event was expected to contain the inserted content (hash: %s)", second.Hash().String())
+					return
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("the commit was expected to be detected")
+				return
+			}
+
+			// the context is refreshed before its next read:
+			_, err = app.Retrieve(*pContext, second.Kind(), second.Hash())
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			reference, err := app.Reference(*pContext)
+			if err != nil {
+				t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+				return
+			}
+
+			if len(reference.Commits().List()) != 2 {
+				t.Errorf("the context was expected to contain %d commits, %d returned", 2, len(reference.Commits().List()))
+				return
+			}
+
+			// once cancelled, the channel is closed:
+			cancelFn()
+			for range events {
+			}
+		})
+	}
+}
+
+func TestWatch_withInvalidInterval_returnsError(t *testing.T) {
+	dirPath := "./test_files"
+	defer func() {
+		os.RemoveAll(dirPath)
+	}()
+
+	name := "my_name"
+	app := newApplicationForTests(t, dirPath, nil)
+	if app == nil {
+		return
+	}
+
+	err := app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, _, err = app.Watch(name, 0)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	_, _, err = app.Watch("not_a_database", time.Second)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+}
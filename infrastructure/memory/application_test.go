@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/applications/conformance"
+)
+
+func TestApplication_Conformance(t *testing.T) {
+	conformance.Execute(t, func() (databases.Application, func()) {
+		app, err := NewApplication(nil, nil, nil, nil)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return nil, func() {}
+		}
+
+		return app, func() {}
+	})
+}
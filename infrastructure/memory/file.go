@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+type file struct {
+	mutex *sync.RWMutex
+	data  []byte
+}
+
+func createFile() *file {
+	out := file{
+		mutex: &sync.RWMutex{},
+		data:  []byte{},
+	}
+
+	return &out
+}
+
+func (obj *file) readAt(data []byte, offset int64) (int, error) {
+	obj.mutex.RLock()
+	defer obj.mutex.RUnlock()
+
+	if offset < 0 {
+		return 0, errors.New("the offset must be positive in order to read a file")
+	}
+
+	if offset >= int64(len(obj.data)) {
+		return 0, io.EOF
+	}
+
+	amount := copy(data, obj.data[offset:])
+	if amount < len(data) {
+		return amount, io.EOF
+	}
+
+	return amount, nil
+}
+
+func (obj *file) writeAt(data []byte, offset int64) (int, error) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+
+	if offset < 0 {
+		return 0, errors.New("the offset must be positive in order to write a file")
+	}
+
+	endsOn := int(offset) + len(data)
+	if endsOn > len(obj.data) {
+		obj.data = append(obj.data, make([]byte, endsOn-len(obj.data))...)
+	}
+
+	return copy(obj.data[offset:], data), nil
+}
+
+func (obj *file) size() int64 {
+	obj.mutex.RLock()
+	defer obj.mutex.RUnlock()
+	return int64(len(obj.data))
+}
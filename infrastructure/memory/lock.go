@@ -0,0 +1,26 @@
+package memory
+
+type lock struct {
+	storage *storage
+	file    *file
+	path    string
+}
+
+func createLock(
+	storage *storage,
+	file *file,
+	path string,
+) *lock {
+	out := lock{
+		storage: storage,
+		file:    file,
+		path:    path,
+	}
+
+	return &out
+}
+
+// Unlock releases the lock
+func (obj *lock) Unlock() error {
+	return obj.storage.unlock(obj)
+}
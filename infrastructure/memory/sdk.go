@@ -0,0 +1,37 @@
+package memory
+
+import (
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+const dstExtension = "destination"
+const bckExtension = "backup"
+const readChunkSize = 1000000
+
+// NewApplication creates a new memory application instance
+func NewApplication(
+	onOpenFn databases.OnOpenFn,
+	onBeforeCommitFn databases.OnBeforeCommitFn,
+	onAfterCommitFn databases.OnAfterCommitFn,
+	instrumentation databases.Instrumentation,
+) (databases.Application, error) {
+	storage := NewStorage()
+	return files.NewApplicationWithStorage(
+		storage,
+		"",
+		dstExtension,
+		bckExtension,
+		readChunkSize,
+		onOpenFn,
+		onBeforeCommitFn,
+		onAfterCommitFn,
+		instrumentation,
+		nil,
+	)
+}
+
+// NewStorage creates a new memory storage instance
+func NewStorage() files.Storage {
+	return createStorage()
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+type storage struct {
+	mutex *sync.Mutex
+	files map[string]*file
+	locks map[*file]*lock
+}
+
+func createStorage() files.Storage {
+	out := storage{
+		mutex: &sync.Mutex{},
+		files: map[string]*file{},
+		locks: map[*file]*lock{},
+	}
+
+	return &out
+}
+
+// Exists returns true if the path is an existing file, false otherwise
+func (app *storage) Exists(path string) (bool, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	_, ok := app.files[path]
+	return ok, nil
+}
+
+// Create creates an empty file
+func (app *storage) Create(path string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.files[path] = createFile()
+	return nil
+}
+
+// Open opens an existing file for reading and writing
+func (app *storage) Open(path string) (files.StorageFile, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if pFile, ok := app.files[path]; ok {
+		return createStorageFile(pFile), nil
+	}
+
+	str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be opened", path)
+	return nil, errors.New(str)
+}
+
+// Remove removes an existing file, the opened files remain readable
+func (app *storage) Remove(path string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if _, ok := app.files[path]; !ok {
+		str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be removed", path)
+		return errors.New(str)
+	}
+
+	delete(app.files, path)
+	return nil
+}
+
+// Rename renames a file
+func (app *storage) Rename(from string, to string) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if pFile, ok := app.files[from]; ok {
+		app.files[to] = pFile
+		delete(app.files, from)
+		return nil
+	}
+
+	str := fmt.Sprintf("the file (path: %s) does not exists and therefore cannot be renamed", from)
+	return errors.New(str)
+}
+
+// SyncDir does nothing, since the files are kept in memory
+func (app *storage) SyncDir(path string) error {
+	return nil
+}
+
+// Lock acquires the lock on the file of a path, creating it when missing, or returns an error if it is already locked.
+// Like a file lock of the operating system, the lock follows the file: replacing the path by another file does not
+// carry the lock
+func (app *storage) Lock(path string) (files.StorageLock, error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	pFile, ok := app.files[path]
+	if !ok {
+		pFile = createFile()
+		app.files[path] = pFile
+	}
+
+	if _, ok := app.locks[pFile]; ok {
+		str := fmt.Sprintf("the path (%s) is already locked", path)
+		return nil, errors.New(str)
+	}
+
+	pLock := createLock(app, pFile, path)
+	app.locks[pFile] = pLock
+	return pLock, nil
+}
+
+func (app *storage) unlock(pLock *lock) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if current, ok := app.locks[pLock.file]; ok && current == pLock {
+		delete(app.locks, pLock.file)
+		return nil
+	}
+
+	str := fmt.Sprintf("the path (%s) is not locked by this lock and therefore cannot be unlocked", pLock.path)
+	return errors.New(str)
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"errors"
+
+	"github.com/steve-care-software/databases/infrastructure/files"
+)
+
+type storageFile struct {
+	pFile    *file
+	isClosed bool
+}
+
+func createStorageFile(
+	pFile *file,
+) files.StorageFile {
+	out := storageFile{
+		pFile:    pFile,
+		isClosed: false,
+	}
+
+	return &out
+}
+
+// ReadAt reads data at offset
+func (obj *storageFile) ReadAt(data []byte, offset int64) (int, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore cannot be read")
+	}
+
+	return obj.pFile.readAt(data, offset)
+}
+
+// WriteAt writes data at offset
+func (obj *storageFile) WriteAt(data []byte, offset int64) (int, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore cannot be written")
+	}
+
+	return obj.pFile.writeAt(data, offset)
+}
+
+// Size returns the size of the file
+func (obj *storageFile) Size() (int64, error) {
+	if obj.isClosed {
+		return 0, errors.New("the file is closed and therefore its size cannot be returned")
+	}
+
+	return obj.pFile.size(), nil
+}
+
+// Sync does nothing since the data is always in memory
+func (obj *storageFile) Sync() error {
+	if obj.isClosed {
+		return errors.New("the file is closed and therefore cannot be synced")
+	}
+
+	return nil
+}
+
+// Close closes the file
+func (obj *storageFile) Close() error {
+	if obj.isClosed {
+		return errors.New("the file is already closed")
+	}
+
+	obj.isClosed = true
+	return nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStorage_removeWhileOpen_thenRename_Success(t *testing.T) {
+	storage := NewStorage()
+	err := storage.Create("first")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	file, err := storage.Open("first")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	expected := []byte("this is some data")
+	_, err = file.WriteAt(expected, 0)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = storage.Rename("first", "second")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = storage.Remove("second")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	exists, err := storage.Exists("second")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if exists {
+		t.Errorf("the file was expected to NOT exists")
+		return
+	}
+
+	data := make([]byte, len(expected))
+	_, err = file.ReadAt(data, 0)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !bytes.Equal(expected, data) {
+		t.Errorf("the data was expected to be %s, %s returned", expected, data)
+		return
+	}
+}
+
+func TestStorage_lock_thenUnlock_Success(t *testing.T) {
+	storage := NewStorage()
+	lock, err := storage.Lock("my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = storage.Lock("my_path")
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = lock.Unlock()
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	_, err = storage.Lock("my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+}
+
+func TestStorage_lock_createsFile_thenReplaceFile_lockDoesNotFollowPath(t *testing.T) {
+	storage := NewStorage()
+	_, err := storage.Lock("my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	exists, err := storage.Exists("my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	if !exists {
+		t.Errorf("the locked path was expected to exists")
+		return
+	}
+
+	err = storage.Create("my_other_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	err = storage.Rename("my_other_path", "my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	_, err = storage.Lock("my_path")
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+}
@@ -0,0 +1,31 @@
+package prometheus
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func createHistogram(buckets []float64) *histogram {
+	out := histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+		sum:     0,
+		count:   0,
+	}
+
+	return &out
+}
+
+// observe adds the value to the buckets it is lower than or equal to
+func (obj *histogram) observe(value float64) {
+	for index, oneBucket := range obj.buckets {
+		if value <= oneBucket {
+			obj.counts[index]++
+		}
+	}
+
+	obj.sum += value
+	obj.count++
+}
@@ -0,0 +1,211 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	databases "github.com/steve-care-software/databases/applications"
+)
+
+type instrumentation struct {
+	mutex           sync.Mutex
+	durationBuckets []float64
+	durations       map[string]*histogram
+	amounts         map[string]uint64
+	errors          map[string]uint64
+	inFlight        map[string]int64
+	lockWaits       map[string]*histogram
+	commitSizes     *histogram
+	commitInserts   uint64
+	commitDeletes   uint64
+}
+
+func createInstrumentation(
+	operations []string,
+	durationBuckets []float64,
+	sizeBuckets []float64,
+) Instrumentation {
+	out := instrumentation{
+		durationBuckets: durationBuckets,
+		durations:       map[string]*histogram{},
+		amounts:         map[string]uint64{},
+		errors:          map[string]uint64{},
+		inFlight:        map[string]int64{},
+		lockWaits: map[string]*histogram{
+			lockOutcomeAcquired: createHistogram(durationBuckets),
+			lockOutcomeFailed:   createHistogram(durationBuckets),
+		},
+		commitSizes:   createHistogram(sizeBuckets),
+		commitInserts: 0,
+		commitDeletes: 0,
+	}
+
+	// the known operations are exposed before their first measurement:
+	for _, oneOperation := range operations {
+		out.durations[oneOperation] = createHistogram(durationBuckets)
+		out.amounts[oneOperation] = 0
+		out.errors[oneOperation] = 0
+		out.inFlight[oneOperation] = 0
+	}
+
+	return &out
+}
+
+// StartSpan starts the span of an operation, the operation is in flight until its span is ended
+func (app *instrumentation) StartSpan(operation string, name string) databases.Span {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.inFlight[operation]++
+	return createSpan(app, operation)
+}
+
+// RecordOperation records the latency of an operation, the amount of bytes it read or wrote, and its error, if any
+func (app *instrumentation) RecordOperation(operation string, duration time.Duration, amount uint, err error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if _, ok := app.durations[operation]; !ok {
+		app.durations[operation] = createHistogram(app.durationBuckets)
+	}
+
+	app.durations[operation].observe(duration.Seconds())
+	app.amounts[operation] += uint64(amount)
+	if err != nil {
+		app.errors[operation]++
+	}
+}
+
+// RecordLockWait records the time spent attempting to acquire the lock of a database, by outcome
+func (app *instrumentation) RecordLockWait(duration time.Duration, err error) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	outcome := lockOutcomeAcquired
+	if err != nil {
+		outcome = lockOutcomeFailed
+	}
+
+	app.lockWaits[outcome].observe(duration.Seconds())
+}
+
+// RecordCommit records the amount of bytes a commit appended to the data region, and its amount of inserts and deletes
+func (app *instrumentation) RecordCommit(size uint, inserts uint, deletes uint) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.commitSizes.observe(float64(size))
+	app.commitInserts += uint64(inserts)
+	app.commitDeletes += uint64(deletes)
+}
+
+// ServeHTTP writes the measurements in the Prometheus text format
+func (app *instrumentation) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	buffer := bytes.Buffer{}
+	err := app.Write(&buffer)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", contentType)
+	writer.Write(buffer.Bytes())
+}
+
+// Write writes the measurements in the Prometheus text format
+func (app *instrumentation) Write(writer io.Writer) error {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	operations := []string{}
+	for oneOperation := range app.durations {
+		operations = append(operations, oneOperation)
+	}
+
+	sort.Strings(operations)
+	buffer := bytes.Buffer{}
+	writeHeader(&buffer, "operation_duration_seconds", "histogram", "The latency of the operations, in seconds.")
+	for _, oneOperation := range operations {
+		writeHistogram(&buffer, "operation_duration_seconds", createOperationLabel(oneOperation), app.durations[oneOperation])
+	}
+
+	writeHeader(&buffer, "operation_bytes_total", "counter", "The amount of bytes read or written by the operations.")
+	for _, oneOperation := range operations {
+		writeSample(&buffer, "operation_bytes_total", createOperationLabel(oneOperation), strconv.FormatUint(app.amounts[oneOperation], 10))
+	}
+
+	writeHeader(&buffer, "operation_errors_total", "counter", "The amount of operations that returned an error.")
+	for _, oneOperation := range operations {
+		writeSample(&buffer, "operation_errors_total", createOperationLabel(oneOperation), strconv.FormatUint(app.errors[oneOperation], 10))
+	}
+
+	writeHeader(&buffer, "operations_in_flight", "gauge", "The amount of operations started and not yet ended.")
+	for _, oneOperation := range operations {
+		writeSample(&buffer, "operations_in_flight", createOperationLabel(oneOperation), strconv.FormatInt(app.inFlight[oneOperation], 10))
+	}
+
+	writeHeader(&buffer, "lock_wait_seconds", "histogram", "The time spent attempting to acquire the lock of the databases, by outcome, in seconds.")
+	for _, oneOutcome := range []string{lockOutcomeAcquired, lockOutcomeFailed} {
+		writeHistogram(&buffer, "lock_wait_seconds", createOutcomeLabel(oneOutcome), app.lockWaits[oneOutcome])
+	}
+
+	writeHeader(&buffer, "commit_size_bytes", "histogram", "The amount of bytes the commits appended to the data region.")
+	writeHistogram(&buffer, "commit_size_bytes", "", app.commitSizes)
+
+	writeHeader(&buffer, "commit_inserts_total", "counter", "The amount of contents inserted by the commits.")
+	writeSample(&buffer, "commit_inserts_total", "", strconv.FormatUint(app.commitInserts, 10))
+
+	writeHeader(&buffer, "commit_deletes_total", "counter", "The amount of contents deleted by the commits.")
+	writeSample(&buffer, "commit_deletes_total", "", strconv.FormatUint(app.commitDeletes, 10))
+
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func writeHeader(buffer *bytes.Buffer, name string, kind string, help string) {
+	fmt.Fprintf(buffer, "# HELP %s_%s %s\n", namespace, name, help)
+	fmt.Fprintf(buffer, "# TYPE %s_%s %s\n", namespace, name, kind)
+}
+
+// writeHistogram writes the cumulative buckets of the histogram, then its sum and count
+func writeHistogram(buffer *bytes.Buffer, name string, labels string, histogram *histogram) {
+	for index, oneBucket := range histogram.buckets {
+		bucketLabels := appendLabel(labels, fmt.Sprintf("le=\"%s\"", formatFloat(oneBucket)))
+		writeSample(buffer, fmt.Sprintf("%s_bucket", name), bucketLabels, strconv.FormatUint(histogram.counts[index], 10))
+	}
+
+	writeSample(buffer, fmt.Sprintf("%s_bucket", name), appendLabel(labels, "le=\"+Inf\""), strconv.FormatUint(histogram.count, 10))
+	writeSample(buffer, fmt.Sprintf("%s_sum", name), labels, formatFloat(histogram.sum))
+	writeSample(buffer, fmt.Sprintf("%s_count", name), labels, strconv.FormatUint(histogram.count, 10))
+}
+
+func writeSample(buffer *bytes.Buffer, name string, labels string, value string) {
+	if labels == "" {
+		fmt.Fprintf(buffer, "%s_%s %s\n", namespace, name, value)
+		return
+	}
+
+	fmt.Fprintf(buffer, "%s_%s{%s} %s\n", namespace, name, labels, value)
+}
+
+func appendLabel(labels string, label string) string {
+	if labels == "" {
+		return label
+	}
+
+	return fmt.Sprintf("%s,%s", labels, label)
+}
+
+func createOperationLabel(operation string) string {
+	return fmt.Sprintf("operation=%s", strconv.Quote(operation))
+}
+
+func createOutcomeLabel(outcome string) string {
+	return fmt.Sprintf("outcome=%s", strconv.Quote(outcome))
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
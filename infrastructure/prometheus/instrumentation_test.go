@@ -0,0 +1,195 @@
+package prometheus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	databases "github.com/steve-care-software/databases/applications"
+	"github.com/steve-care-software/databases/domain/contents"
+	"github.com/steve-care-software/databases/infrastructure/files"
+	"github.com/steve-care-software/databases/infrastructure/memory"
+)
+
+func TestInstrumentation_withApplication_thenServe_Success(t *testing.T) {
+	instrumentation := NewInstrumentation()
+	storage := memory.NewStorage()
+	app, err := files.NewApplicationWithStorage(storage, "", "destination", "backup", 10, nil, nil, nil, instrumentation, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	other, err := files.NewApplicationWithStorage(storage, "", "destination", "backup", 10, nil, nil, nil, instrumentation, nil)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	name := "my_name"
+	err = app.New(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	pContext, err := app.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer app.Close(*pContext)
+	err = app.Lock(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	for _, oneContent := range []contents.Content{
+		contents.NewContentForTests(0, []byte("this is the first data")),
+		contents.NewContentForTests(1, []byte("this is the second data")),
+	} {
+		err = app.Insert(*pContext, oneContent)
+		if err != nil {
+			t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+			return
+		}
+	}
+
+	err = app.Commit(*pContext)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	// an empty commit returns an error:
+	err = app.Commit(*pContext)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	// the lock is already held, therefore the attempt of another application fails:
+	pOther, err := other.Open(name)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer other.Close(*pOther)
+	err = other.Lock(*pOther)
+	if err == nil {
+		t.Errorf("the error was expected to be valid, nil returned")
+		return
+	}
+
+	err = app.Write(*pContext, 0, []byte("data"))
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	server := httptest.NewServer(instrumentation)
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	defer response.Body.Close()
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("the content type (%s) is invalid", response.Header.Get("Content-Type"))
+		return
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return
+	}
+
+	expected := []string{
+		"# TYPE databases_operation_duration_seconds histogram",
+		`databases_operation_duration_seconds_count{operation="open"} 2`,
+		`databases_operation_duration_seconds_count{operation="commit"} 2`,
+		`databases_operation_duration_seconds_bucket{operation="commit",le="+Inf"} 2`,
+		`databases_operation_errors_total{operation="commit"} 1`,
+		`databases_operation_errors_total{operation="open"} 0`,
+		`databases_operation_bytes_total{operation="write"} 4`,
+		`databases_lock_wait_seconds_count{outcome="acquired"} 1`,
+		`databases_lock_wait_seconds_count{outcome="failed"} 1`,
+		`databases_operation_errors_total{operation="lock"} 1`,
+		`databases_operations_in_flight{operation="commit"} 0`,
+		"databases_commit_size_bytes_count 1",
+		"databases_commit_inserts_total 2",
+		"databases_commit_deletes_total 0",
+	}
+
+	output := string(body)
+	for _, oneLine := range expected {
+		if !strings.Contains(output, oneLine+"\n") {
+			t.Errorf("the output was expected to contain the line: %s", oneLine)
+			return
+		}
+	}
+}
+
+func TestInstrumentation_startSpan_thenEnd_Success(t *testing.T) {
+	instrumentation := NewInstrumentation()
+	first := instrumentation.StartSpan(databases.OperationRead, "my_name")
+	second := instrumentation.StartSpan(databases.OperationRead, "my_name")
+	if !writeContainsForTests(t, instrumentation, `databases_operations_in_flight{operation="read"} 2`) {
+		return
+	}
+
+	// ending a span more than once has no effect:
+	first.End(nil)
+	first.End(nil)
+	if !writeContainsForTests(t, instrumentation, `databases_operations_in_flight{operation="read"} 1`) {
+		return
+	}
+
+	second.End(errors.New("this is an error"))
+	if !writeContainsForTests(t, instrumentation, `databases_operations_in_flight{operation="read"} 0`) {
+		return
+	}
+}
+
+func writeContainsForTests(t *testing.T, instrumentation Instrumentation, line string) bool {
+	buffer := bytes.Buffer{}
+	err := instrumentation.Write(&buffer)
+	if err != nil {
+		t.Errorf("the error was expected to be nil, error returned: %s", err.Error())
+		return false
+	}
+
+	if !strings.Contains(buffer.String(), line+"\n") {
+		t.Errorf("the output was expected to contain the line: %s", line)
+		return false
+	}
+
+	return true
+}
+
+func TestHistogram_observe_isCumulative_Success(t *testing.T) {
+	histogram := createHistogram([]float64{1, 10})
+	for _, oneValue := range []float64{0.5, 5, 50} {
+		histogram.observe(oneValue)
+	}
+
+	if histogram.counts[0] != 1 || histogram.counts[1] != 2 || histogram.count != 3 {
+		t.Errorf("the histogram counts are invalid")
+		return
+	}
+
+	if histogram.sum != 55.5 {
+		t.Errorf("the histogram sum was expected to be %f, %f returned", 55.5, histogram.sum)
+		return
+	}
+}
@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"io"
+	"net/http"
+
+	databases "github.com/steve-care-software/databases/applications"
+)
+
+const namespace = "databases"
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+const lockOutcomeAcquired = "acquired"
+const lockOutcomeFailed = "failed"
+
+// NewInstrumentation creates a new instrumentation that keeps its measurements in memory and exposes them in the
+// Prometheus text format
+func NewInstrumentation() Instrumentation {
+	operations := []string{
+		databases.OperationOpen,
+		databases.OperationRead,
+		databases.OperationWrite,
+		databases.OperationCommit,
+		databases.OperationLock,
+	}
+
+	durationBuckets := []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+	sizeBuckets := []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216}
+	return createInstrumentation(operations, durationBuckets, sizeBuckets)
+}
+
+// Instrumentation represents an instrumentation exposing its measurements in the Prometheus text format.  It can
+// be served over http as the metrics endpoint of a scraper
+type Instrumentation interface {
+	databases.Instrumentation
+	http.Handler
+
+	// Write writes the measurements in the Prometheus text format
+	Write(writer io.Writer) error
+}
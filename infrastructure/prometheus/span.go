@@ -0,0 +1,29 @@
+package prometheus
+
+type span struct {
+	instrumentation *instrumentation
+	operation       string
+	isEnded         bool
+}
+
+func createSpan(instrumentation *instrumentation, operation string) *span {
+	out := span{
+		instrumentation: instrumentation,
+		operation:       operation,
+		isEnded:         false,
+	}
+
+	return &out
+}
+
+// End ends the span, its operation is no longer in flight.  Ending a span more than once has no effect
+func (obj *span) End(err error) {
+	obj.instrumentation.mutex.Lock()
+	defer obj.instrumentation.mutex.Unlock()
+	if obj.isEnded {
+		return
+	}
+
+	obj.isEnded = true
+	obj.instrumentation.inFlight[obj.operation]--
+}